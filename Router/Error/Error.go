@@ -2,7 +2,10 @@
 // Supports structured error management based on error codes and detailed error messages.
 package Error
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+)
 
 // ErrorCode is an enumeration that distinguishes the types of errors that occur in LiteFrame.
 // Categorizes errors by category to facilitate debugging and problem solving.
@@ -28,9 +31,16 @@ const (
 	ConflictingRoute                          // Conflicts with existing route
 
 	// Runtime errors (4000s)
-	HandlerNotFound  ErrorCode = iota + 4000 // Handler not found
-	MethodNotAllowed                         // HTTP method not allowed
-	ParameterMissing                         // Required parameter missing
+	HandlerNotFound      ErrorCode = iota + 4000 // Handler not found
+	MethodNotAllowed                             // HTTP method not allowed
+	ParameterMissing                             // Required parameter missing
+	RequestTimeout                               // Handler exceeded its deadline
+	TooManyRequests                              // Client exceeded its rate limit
+	Overloaded                                   // Server shed the request under load
+	RequestTooLarge                              // Request body exceeds the size limit
+	UnsupportedMediaType                         // Request content type is not accepted
+	Unauthorized                                 // Request lacks valid credentials
+	Forbidden                                    // Request is not permitted
 )
 
 // LiteFrameError is a structure representing structured errors that occur in LiteFrame.
@@ -39,14 +49,61 @@ type LiteFrameError struct {
 	Code    ErrorCode // Code for error classification
 	Message string    // Detailed description of the error
 	Path    string    // Route path where the error occurred
+	Cause   error     // Underlying error (nil if none), exposed through Unwrap
 }
 
+// Sentinel errors: One value per ErrorCode for use with errors.Is.
+// Any LiteFrameError matches the sentinel of its Code regardless of Message, Path and Cause.
+//
+// Usage example:
+//
+//	if errors.Is(err, Error.ErrConflictingRoute) { ... }
+var (
+	ErrInvalidParameter     = &LiteFrameError{Code: InvalidParameter, Message: GetErrorMessage(InvalidParameter)}
+	ErrNilParameter         = &LiteFrameError{Code: NilParameter, Message: GetErrorMessage(NilParameter)}
+	ErrInvalidMethod        = &LiteFrameError{Code: InvalidMethod, Message: GetErrorMessage(InvalidMethod)}
+	ErrInvalidHandler       = &LiteFrameError{Code: InvalidHandler, Message: GetErrorMessage(InvalidHandler)}
+	ErrSplitFailed          = &LiteFrameError{Code: SplitFailed, Message: GetErrorMessage(SplitFailed)}
+	ErrNodeNotFound         = &LiteFrameError{Code: NodeNotFound, Message: GetErrorMessage(NodeNotFound)}
+	ErrPathTooLong          = &LiteFrameError{Code: PathTooLong, Message: GetErrorMessage(PathTooLong)}
+	ErrInvalidSplitPoint    = &LiteFrameError{Code: InvalidSplitPoint, Message: GetErrorMessage(InvalidSplitPoint)}
+	ErrDuplicateWildCard    = &LiteFrameError{Code: DuplicateWildCard, Message: GetErrorMessage(DuplicateWildCard)}
+	ErrDuplicateCatchAll    = &LiteFrameError{Code: DuplicateCatchAll, Message: GetErrorMessage(DuplicateCatchAll)}
+	ErrConflictingRoute     = &LiteFrameError{Code: ConflictingRoute, Message: GetErrorMessage(ConflictingRoute)}
+	ErrHandlerNotFound      = &LiteFrameError{Code: HandlerNotFound, Message: GetErrorMessage(HandlerNotFound)}
+	ErrMethodNotAllowed     = &LiteFrameError{Code: MethodNotAllowed, Message: GetErrorMessage(MethodNotAllowed)}
+	ErrParameterMissing     = &LiteFrameError{Code: ParameterMissing, Message: GetErrorMessage(ParameterMissing)}
+	ErrRequestTimeout       = &LiteFrameError{Code: RequestTimeout, Message: GetErrorMessage(RequestTimeout)}
+	ErrTooManyRequests      = &LiteFrameError{Code: TooManyRequests, Message: GetErrorMessage(TooManyRequests)}
+	ErrOverloaded           = &LiteFrameError{Code: Overloaded, Message: GetErrorMessage(Overloaded)}
+	ErrRequestTooLarge      = &LiteFrameError{Code: RequestTooLarge, Message: GetErrorMessage(RequestTooLarge)}
+	ErrUnsupportedMediaType = &LiteFrameError{Code: UnsupportedMediaType, Message: GetErrorMessage(UnsupportedMediaType)}
+	ErrUnauthorized         = &LiteFrameError{Code: Unauthorized, Message: GetErrorMessage(Unauthorized)}
+	ErrForbidden            = &LiteFrameError{Code: Forbidden, Message: GetErrorMessage(Forbidden)}
+)
+
 // Error implements the error interface.
 // Returns formatted error message for use in logging and debugging.
+// The cause, if any, is appended after a colon.
 func (e *LiteFrameError) Error() string {
+	if e.Cause != nil {
+		return fmt.Sprintf("LiteFrame Error [%d]: %s (Path: %s): %v", e.Code, e.Message, e.Path, e.Cause)
+	}
 	return fmt.Sprintf("LiteFrame Error [%d]: %s (Path: %s)", e.Code, e.Message, e.Path)
 }
 
+// Is reports whether target is a LiteFrameError with the same Code.
+// Makes errors.Is(err, ErrXxx) work for every error created by this package.
+func (e *LiteFrameError) Is(target error) bool {
+	other, ok := target.(*LiteFrameError)
+	return ok && other.Code == e.Code
+}
+
+// Unwrap returns the underlying cause so errors.Is / errors.As can inspect it.
+func (e *LiteFrameError) Unwrap() error {
+	return e.Cause
+}
+
 // NewError creates a new LiteFrameError.
 // Used when creating errors with custom messages.
 func NewError(code ErrorCode, message string, path string) error {
@@ -96,6 +153,20 @@ func GetErrorMessage(code ErrorCode) string {
 		return "HTTP method not allowed"
 	case ParameterMissing:
 		return "Required parameter is missing"
+	case RequestTimeout:
+		return "Request processing timed out"
+	case TooManyRequests:
+		return "Rate limit exceeded"
+	case Overloaded:
+		return "Server is overloaded"
+	case RequestTooLarge:
+		return "Request body too large"
+	case UnsupportedMediaType:
+		return "Unsupported media type"
+	case Unauthorized:
+		return "Authentication required"
+	case Forbidden:
+		return "Access forbidden"
 
 	default:
 		return "Unknown error"
@@ -106,3 +177,24 @@ func GetErrorMessage(code ErrorCode) string {
 func NewErrorWithCode(code ErrorCode, path string) error {
 	return NewError(code, GetErrorMessage(code), path)
 }
+
+// WithCause creates a new error with the default message of code that wraps cause.
+// Used when a LiteFrame operation fails because of an underlying error.
+func WithCause(code ErrorCode, path string, cause error) error {
+	return &LiteFrameError{
+		Code:    code,
+		Message: GetErrorMessage(code),
+		Path:    path,
+		Cause:   cause,
+	}
+}
+
+// Code returns the ErrorCode of the first LiteFrameError in err's chain.
+// Returns 0 if err is nil or contains no LiteFrameError.
+func Code(err error) ErrorCode {
+	var liteErr *LiteFrameError
+	if errors.As(err, &liteErr) {
+		return liteErr.Code
+	}
+	return 0
+}
@@ -0,0 +1,129 @@
+// Package Error provides HTTP status mapping and RFC 9457 problem details responses.
+// Converts LiteFrameError codes into consistent application/problem+json responses.
+package Error
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// ProblemContentType is the media type of RFC 9457 problem details documents.
+const ProblemContentType = "application/problem+json"
+
+// Problem is an RFC 9457 problem details document.
+// Code is an extension member carrying the LiteFrame ErrorCode when available.
+type Problem struct {
+	Type     string    `json:"type"`               // URI reference identifying the problem type
+	Title    string    `json:"title"`              // Short summary of the problem type
+	Status   int       `json:"status"`             // HTTP status code
+	Detail   string    `json:"detail,omitempty"`   // Explanation specific to this occurrence
+	Instance string    `json:"instance,omitempty"` // URI reference identifying this occurrence
+	Code     ErrorCode `json:"code,omitempty"`     // LiteFrame error code (extension member)
+}
+
+// HTTPStatus returns the HTTP status code corresponding to an error code.
+// Registration-time errors (tree structure, route conflicts) map to 500 because
+// they indicate a server configuration problem when they reach a client.
+func HTTPStatus(code ErrorCode) int {
+	switch code {
+	// General errors
+	case InvalidParameter, NilParameter:
+		return http.StatusBadRequest
+	case InvalidMethod:
+		return http.StatusNotImplemented
+	case InvalidHandler:
+		return http.StatusInternalServerError
+
+	// Tree structure errors
+	case NodeNotFound:
+		return http.StatusNotFound
+	case PathTooLong:
+		return http.StatusRequestURITooLong
+	case SplitFailed, InvalidSplitPoint:
+		return http.StatusInternalServerError
+
+	// Route conflicts
+	case DuplicateWildCard, DuplicateCatchAll, ConflictingRoute:
+		return http.StatusInternalServerError
+
+	// Runtime errors
+	case HandlerNotFound:
+		return http.StatusNotFound
+	case MethodNotAllowed:
+		return http.StatusMethodNotAllowed
+	case ParameterMissing:
+		return http.StatusBadRequest
+	case RequestTimeout:
+		return http.StatusServiceUnavailable
+	case TooManyRequests:
+		return http.StatusTooManyRequests
+	case Overloaded:
+		return http.StatusServiceUnavailable
+	case RequestTooLarge:
+		return http.StatusRequestEntityTooLarge
+	case UnsupportedMediaType:
+		return http.StatusUnsupportedMediaType
+	case Unauthorized:
+		return http.StatusUnauthorized
+	case Forbidden:
+		return http.StatusForbidden
+
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// NewProblem creates a problem document for status with the default "about:blank" type.
+// Title is the standard status text as recommended by RFC 9457 for "about:blank".
+func NewProblem(status int, detail string, instance string) *Problem {
+	return &Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   detail,
+		Instance: instance,
+	}
+}
+
+// ProblemFromError converts err into a problem document.
+// LiteFrameError values use their code's status and message. *http.MaxBytesError (a body
+// read past http.MaxBytesReader's limit) is reported as RequestTooLarge. Any other error
+// becomes a 500 without detail so that internal error text is not leaked to clients.
+func ProblemFromError(err error, instance string) *Problem {
+	var liteErr *LiteFrameError
+	if errors.As(err, &liteErr) {
+		problem := NewProblem(HTTPStatus(liteErr.Code), liteErr.Message, instance)
+		problem.Code = liteErr.Code
+		return problem
+	}
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		problem := NewProblem(HTTPStatus(RequestTooLarge), GetErrorMessage(RequestTooLarge), instance)
+		problem.Code = RequestTooLarge
+		return problem
+	}
+	return NewProblem(http.StatusInternalServerError, "", instance)
+}
+
+// WriteProblem writes problem as an application/problem+json response.
+func WriteProblem(writer http.ResponseWriter, problem *Problem) {
+	header := writer.Header()
+	header.Set("Content-Type", ProblemContentType)
+	header.Set("X-Content-Type-Options", "nosniff")
+	writer.WriteHeader(problem.Status)
+	_ = json.NewEncoder(writer).Encode(problem)
+}
+
+// WriteError writes err as a problem details response for request.
+// The request path is used as the problem instance.
+//
+// Usage example:
+//
+//	if _, err := params.Require("id"); err != nil {
+//	    Error.WriteError(writer, request, err)
+//	    return
+//	}
+func WriteError(writer http.ResponseWriter, request *http.Request, err error) {
+	WriteProblem(writer, ProblemFromError(err, request.URL.Path))
+}
@@ -296,9 +296,16 @@ func TestGetErrorMessage(t *testing.T) {
 
 	t.Run("runtime_error_messages", func(t *testing.T) {
 		testCases := map[ErrorCode]string{
-			HandlerNotFound:  "Handler not found for route",
-			MethodNotAllowed: "HTTP method not allowed",
-			ParameterMissing: "Required parameter is missing",
+			HandlerNotFound:      "Handler not found for route",
+			MethodNotAllowed:     "HTTP method not allowed",
+			ParameterMissing:     "Required parameter is missing",
+			RequestTimeout:       "Request processing timed out",
+			TooManyRequests:      "Rate limit exceeded",
+			Overloaded:           "Server is overloaded",
+			RequestTooLarge:      "Request body too large",
+			UnsupportedMediaType: "Unsupported media type",
+			Unauthorized:         "Authentication required",
+			Forbidden:            "Access forbidden",
 		}
 
 		for code, expected := range testCases {
@@ -366,7 +373,7 @@ func TestNewErrorWithCode(t *testing.T) {
 			InvalidParameter, NilParameter, InvalidMethod, InvalidHandler,
 			SplitFailed, NodeNotFound, PathTooLong, InvalidSplitPoint,
 			DuplicateWildCard, DuplicateCatchAll, ConflictingRoute,
-			HandlerNotFound, MethodNotAllowed, ParameterMissing,
+			HandlerNotFound, MethodNotAllowed, ParameterMissing, RequestTimeout, TooManyRequests, Overloaded, RequestTooLarge, UnsupportedMediaType, Unauthorized, Forbidden,
 		}
 
 		for _, code := range testCodes {
@@ -504,6 +511,103 @@ func TestErrorIntegration(t *testing.T) {
 	})
 }
 
+// ======================
+// errors.Is / errors.As Tests
+// ======================
+
+func TestSentinelErrors(t *testing.T) {
+	t.Run("is_matches_code", func(t *testing.T) {
+		err := NewError(ConflictingRoute, "custom message", "/users/:id")
+		if !errors.Is(err, ErrConflictingRoute) {
+			t.Error("Expected error to match ErrConflictingRoute")
+		}
+		if errors.Is(err, ErrDuplicateWildCard) {
+			t.Error("Expected error not to match ErrDuplicateWildCard")
+		}
+	})
+
+	t.Run("is_through_wrapping", func(t *testing.T) {
+		err := fmt.Errorf("registering routes: %w", NewErrorWithCode(PathTooLong, "/long"))
+		if !errors.Is(err, ErrPathTooLong) {
+			t.Error("Expected wrapped error to match ErrPathTooLong")
+		}
+	})
+
+	t.Run("sentinels_have_default_messages", func(t *testing.T) {
+		sentinels := map[ErrorCode]*LiteFrameError{
+			InvalidParameter: ErrInvalidParameter, NilParameter: ErrNilParameter,
+			InvalidMethod: ErrInvalidMethod, InvalidHandler: ErrInvalidHandler,
+			SplitFailed: ErrSplitFailed, NodeNotFound: ErrNodeNotFound,
+			PathTooLong: ErrPathTooLong, InvalidSplitPoint: ErrInvalidSplitPoint,
+			DuplicateWildCard: ErrDuplicateWildCard, DuplicateCatchAll: ErrDuplicateCatchAll,
+			ConflictingRoute: ErrConflictingRoute, HandlerNotFound: ErrHandlerNotFound,
+			MethodNotAllowed: ErrMethodNotAllowed, ParameterMissing: ErrParameterMissing,
+			RequestTimeout:       ErrRequestTimeout,
+			TooManyRequests:      ErrTooManyRequests,
+			Overloaded:           ErrOverloaded,
+			RequestTooLarge:      ErrRequestTooLarge,
+			UnsupportedMediaType: ErrUnsupportedMediaType,
+			Unauthorized:         ErrUnauthorized,
+			Forbidden:            ErrForbidden,
+		}
+		for code, sentinel := range sentinels {
+			if sentinel.Code != code || sentinel.Message != GetErrorMessage(code) {
+				t.Errorf("Sentinel for code %d has code %d and message %q", code, sentinel.Code, sentinel.Message)
+			}
+		}
+	})
+}
+
+func TestWithCause(t *testing.T) {
+	cause := errors.New("disk full")
+	err := WithCause(InvalidHandler, "/upload", cause)
+
+	t.Run("unwrap_exposes_cause", func(t *testing.T) {
+		if !errors.Is(err, cause) {
+			t.Error("Expected errors.Is to find the cause")
+		}
+		if !errors.Is(err, ErrInvalidHandler) {
+			t.Error("Expected errors.Is to match ErrInvalidHandler")
+		}
+	})
+
+	t.Run("message_includes_cause", func(t *testing.T) {
+		expected := fmt.Sprintf("LiteFrame Error [%d]: %s (Path: /upload): disk full", InvalidHandler, GetErrorMessage(InvalidHandler))
+		if err.Error() != expected {
+			t.Errorf("Expected '%s', got '%s'", expected, err.Error())
+		}
+	})
+
+	t.Run("nested_lite_frame_errors", func(t *testing.T) {
+		outer := WithCause(ConflictingRoute, "/a", NewErrorWithCode(DuplicateWildCard, "/a/:id"))
+		if !errors.Is(outer, ErrDuplicateWildCard) || !errors.Is(outer, ErrConflictingRoute) {
+			t.Error("Expected both outer and inner codes to match")
+		}
+	})
+}
+
+func TestCode(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		expected ErrorCode
+	}{
+		{"nil", nil, 0},
+		{"plain_error", errors.New("plain"), 0},
+		{"lite_frame_error", NewErrorWithCode(HandlerNotFound, "/"), HandlerNotFound},
+		{"wrapped", fmt.Errorf("outer: %w", NewErrorWithCode(MethodNotAllowed, "/")), MethodNotAllowed},
+		{"joined", errors.Join(errors.New("plain"), NewErrorWithCode(NodeNotFound, "/")), NodeNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if code := Code(tc.err); code != tc.expected {
+				t.Errorf("Expected code %d, got %d", tc.expected, code)
+			}
+		})
+	}
+}
+
 // ======================
 // Helper Functions
 // ======================
@@ -0,0 +1,112 @@
+package Error
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// ======================
+// HTTPStatus Tests
+// ======================
+
+func TestHTTPStatus(t *testing.T) {
+	testCases := map[ErrorCode]int{
+		InvalidParameter:     http.StatusBadRequest,
+		NilParameter:         http.StatusBadRequest,
+		InvalidMethod:        http.StatusNotImplemented,
+		InvalidHandler:       http.StatusInternalServerError,
+		SplitFailed:          http.StatusInternalServerError,
+		NodeNotFound:         http.StatusNotFound,
+		PathTooLong:          http.StatusRequestURITooLong,
+		InvalidSplitPoint:    http.StatusInternalServerError,
+		DuplicateWildCard:    http.StatusInternalServerError,
+		DuplicateCatchAll:    http.StatusInternalServerError,
+		ConflictingRoute:     http.StatusInternalServerError,
+		HandlerNotFound:      http.StatusNotFound,
+		MethodNotAllowed:     http.StatusMethodNotAllowed,
+		ParameterMissing:     http.StatusBadRequest,
+		RequestTimeout:       http.StatusServiceUnavailable,
+		TooManyRequests:      http.StatusTooManyRequests,
+		Overloaded:           http.StatusServiceUnavailable,
+		RequestTooLarge:      http.StatusRequestEntityTooLarge,
+		UnsupportedMediaType: http.StatusUnsupportedMediaType,
+		Unauthorized:         http.StatusUnauthorized,
+		Forbidden:            http.StatusForbidden,
+		ErrorCode(9999):      http.StatusInternalServerError,
+	}
+
+	for code, expected := range testCases {
+		if actual := HTTPStatus(code); actual != expected {
+			t.Errorf("Code %d: Expected status %d, got %d", code, expected, actual)
+		}
+	}
+}
+
+// ======================
+// Problem Details Tests
+// ======================
+
+func TestProblemFromError(t *testing.T) {
+	t.Run("lite_frame_error", func(t *testing.T) {
+		problem := ProblemFromError(NewErrorWithCode(ParameterMissing, "/users"), "/users")
+		if problem.Status != http.StatusBadRequest || problem.Title != "Bad Request" {
+			t.Errorf("Unexpected status/title: %d %q", problem.Status, problem.Title)
+		}
+		if problem.Detail != GetErrorMessage(ParameterMissing) || problem.Code != ParameterMissing {
+			t.Errorf("Unexpected detail/code: %q %d", problem.Detail, problem.Code)
+		}
+		if problem.Type != "about:blank" || problem.Instance != "/users" {
+			t.Errorf("Unexpected type/instance: %q %q", problem.Type, problem.Instance)
+		}
+	})
+
+	t.Run("plain_error_is_not_leaked", func(t *testing.T) {
+		problem := ProblemFromError(errors.New("database password rejected"), "/login")
+		if problem.Status != http.StatusInternalServerError || problem.Detail != "" || problem.Code != 0 {
+			t.Errorf("Unexpected problem for plain error: %+v", problem)
+		}
+	})
+
+	t.Run("max_bytes_error", func(t *testing.T) {
+		err := fmt.Errorf("decode body: %w", &http.MaxBytesError{Limit: 10})
+		problem := ProblemFromError(err, "/upload")
+		if problem.Status != http.StatusRequestEntityTooLarge || problem.Code != RequestTooLarge {
+			t.Errorf("Unexpected problem for MaxBytesError: %+v", problem)
+		}
+	})
+}
+
+func TestWriteError(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest("DELETE", "/users/1", nil)
+	WriteError(recorder, request, NewErrorWithCode(MethodNotAllowed, "/users/1"))
+
+	if recorder.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected status 405, got %d", recorder.Code)
+	}
+	if contentType := recorder.Header().Get("Content-Type"); contentType != ProblemContentType {
+		t.Errorf("Expected content type %q, got %q", ProblemContentType, contentType)
+	}
+
+	var decoded map[string]any
+	if err := json.Unmarshal(recorder.Body.Bytes(), &decoded); err != nil {
+		t.Fatalf("Failed to decode body: %v", err)
+	}
+	expected := map[string]any{
+		"type":     "about:blank",
+		"title":    "Method Not Allowed",
+		"status":   float64(http.StatusMethodNotAllowed),
+		"detail":   GetErrorMessage(MethodNotAllowed),
+		"instance": "/users/1",
+		"code":     float64(MethodNotAllowed),
+	}
+	for key, value := range expected {
+		if decoded[key] != value {
+			t.Errorf("Field %s: expected %v, got %v", key, value, decoded[key])
+		}
+	}
+}
@@ -0,0 +1,213 @@
+// Package Middleware provides authentication middleware.
+// Verifies HTTP Basic credentials and Bearer tokens with pluggable validators and
+// places the authenticated principal in the request context.
+package Middleware
+
+import (
+	"LiteFrame/Router/Error"
+	"LiteFrame/Router/Param"
+	"LiteFrame/Router/Types"
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+	"sync/atomic"
+)
+
+// PrincipalKey is an empty structure for identifying the authenticated principal in context.
+// Used as a key for principal storage in context.WithValue.
+type PrincipalKey struct{}
+
+// Principal is the authenticated identity of a request.
+type Principal struct {
+	Subject string         // User or client identifier (username, JWT "sub")
+	Scheme  string         // Authentication scheme that verified it ("Basic", "Bearer")
+	Claims  map[string]any // Verified token claims (nil for Basic)
+}
+
+// GetPrincipal extracts the authenticated principal from context.
+// Returns nil if the request was not authenticated.
+//
+// Usage example:
+//
+//	if principal := Middleware.GetPrincipal(request.Context()); principal != nil {
+//	    log.Println("user:", principal.Subject)
+//	}
+func GetPrincipal(ctx context.Context) *Principal {
+	principal, _ := ctx.Value(PrincipalKey{}).(*Principal)
+	return principal
+}
+
+// WithPrincipal returns a copy of ctx carrying principal.
+// The principal is also reported to an outer middleware that asked for it with
+// withPrincipalSlot (Logger), which cannot see contexts derived downstream.
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	if slot, ok := ctx.Value(principalSlotKey{}).(*principalSlot); ok {
+		slot.principal.Store(principal)
+	}
+	return context.WithValue(ctx, PrincipalKey{}, principal)
+}
+
+// principalSlotKey identifies the principalSlot in context.
+type principalSlotKey struct{}
+
+// principalSlot receives the principal authenticated further down the chain.
+// Atomic because Timeout may still run the handler when the outer middleware reads it.
+type principalSlot struct {
+	principal atomic.Pointer[Principal]
+}
+
+// withPrincipalSlot returns a copy of ctx carrying an empty principalSlot.
+func withPrincipalSlot(ctx context.Context) (context.Context, *principalSlot) {
+	slot := &principalSlot{}
+	return context.WithValue(ctx, principalSlotKey{}, slot), slot
+}
+
+// BasicValidator checks a username and password.
+//
+// It returns the principal for valid credentials, or nil and a nil error for invalid ones,
+// which are answered with 401. A LiteFrameError is written as is (e.g. to answer 403);
+// any other error is treated as an internal failure and answered with 500.
+type BasicValidator func(ctx context.Context, username string, password string) (*Principal, error)
+
+// TokenValidator checks a bearer token. The return values follow BasicValidator.
+type TokenValidator func(ctx context.Context, token string) (*Principal, error)
+
+// BasicUsers returns a BasicValidator accepting the given username/password pairs.
+// Passwords are compared in constant time over their SHA-256 digests, so neither the
+// length of a password nor whether the username exists is revealed by timing.
+func BasicUsers(users map[string]string) BasicValidator {
+	digests := make(map[string][sha256.Size]byte, len(users))
+	for username, password := range users {
+		digests[username] = sha256.Sum256([]byte(password))
+	}
+	return func(_ context.Context, username string, password string) (*Principal, error) {
+		expected, ok := digests[username]
+		given := sha256.Sum256([]byte(password))
+		if subtle.ConstantTimeCompare(given[:], expected[:]) != 1 || !ok {
+			return nil, nil
+		}
+		return &Principal{Subject: username, Scheme: "Basic"}, nil
+	}
+}
+
+// BasicAuth is a middleware that requires HTTP Basic authentication (RFC 7617).
+//
+// Usage example:
+//
+//	admin := tree.Group("/admin", Middleware.NewBasicAuth("admin", Middleware.BasicUsers(map[string]string{
+//	    "alice": os.Getenv("ALICE_PASSWORD"),
+//	})))
+type BasicAuth struct {
+	Realm        string                 // Protection space sent in WWW-Authenticate
+	Validator    BasicValidator         // Credential check (nil: every request is rejected)
+	ErrorHandler Types.ErrorHandlerFunc // Writes rejection responses (nil: the tree's, see HandleError)
+}
+
+// NewBasicAuth creates a BasicAuth middleware for realm using validator.
+func NewBasicAuth(realm string, validator BasicValidator) *BasicAuth {
+	return &BasicAuth{
+		Realm:     realm,
+		Validator: validator,
+	}
+}
+
+// GetHandler implements the Middleware interface.
+func (instance *BasicAuth) GetHandler() MiddleWareFunc {
+	challenge := `Basic realm=` + quoteAuthParam(instance.Realm) + `, charset="UTF-8"`
+
+	return func(next Types.HandlerFunc) Types.HandlerFunc {
+		return func(writer http.ResponseWriter, request *http.Request, params *Param.Params) {
+			username, password, ok := request.BasicAuth()
+			var principal *Principal
+			var err error
+			if ok && instance.Validator != nil {
+				principal, err = instance.Validator(request.Context(), username, password)
+			}
+			if principal == nil {
+				rejectAuth(writer, request, challenge, err, instance.ErrorHandler)
+				return
+			}
+			next(writer, request.WithContext(WithPrincipal(request.Context(), principal)), params)
+		}
+	}
+}
+
+// BearerToken returns the token of a "Bearer" Authorization header (RFC 6750).
+// Returns an empty string if the header is missing or uses another scheme.
+func BearerToken(request *http.Request) string {
+	scheme, token, ok := strings.Cut(request.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+// BearerAuth is a middleware that requires a bearer token (RFC 6750).
+// Use NewJWTAuth for JSON Web Tokens.
+//
+// Usage example:
+//
+//	api := tree.Group("/api", Middleware.NewBearerAuth("api", func(ctx context.Context, token string) (*Middleware.Principal, error) {
+//	    client, err := clients.LookupToken(ctx, token)
+//	    if err != nil || client == nil {
+//	        return nil, err
+//	    }
+//	    return &Middleware.Principal{Subject: client.ID, Scheme: "Bearer"}, nil
+//	}))
+type BearerAuth struct {
+	Realm        string                 // Protection space sent in WWW-Authenticate
+	Validator    TokenValidator         // Token check (nil: every request is rejected)
+	ErrorHandler Types.ErrorHandlerFunc // Writes rejection responses (nil: the tree's, see HandleError)
+}
+
+// NewBearerAuth creates a BearerAuth middleware for realm using validator.
+func NewBearerAuth(realm string, validator TokenValidator) *BearerAuth {
+	return &BearerAuth{
+		Realm:     realm,
+		Validator: validator,
+	}
+}
+
+// GetHandler implements the Middleware interface.
+func (instance *BearerAuth) GetHandler() MiddleWareFunc {
+	challenge := `Bearer realm=` + quoteAuthParam(instance.Realm)
+
+	return func(next Types.HandlerFunc) Types.HandlerFunc {
+		return func(writer http.ResponseWriter, request *http.Request, params *Param.Params) {
+			token := BearerToken(request)
+			var principal *Principal
+			var err error
+			if token != "" && instance.Validator != nil {
+				principal, err = instance.Validator(request.Context(), token)
+			}
+			if principal == nil {
+				if token != "" {
+					rejectAuth(writer, request, challenge+`, error="invalid_token"`, err, instance.ErrorHandler)
+					return
+				}
+				rejectAuth(writer, request, challenge, err, instance.ErrorHandler)
+				return
+			}
+			next(writer, request.WithContext(WithPrincipal(request.Context(), principal)), params)
+		}
+	}
+}
+
+// rejectAuth writes the response for a failed authentication.
+// The challenge is only sent with 401 responses.
+func rejectAuth(writer http.ResponseWriter, request *http.Request, challenge string, err error, handler Types.ErrorHandlerFunc) {
+	if err == nil {
+		err = Error.NewErrorWithCode(Error.Unauthorized, request.URL.Path)
+	}
+	if code := Error.Code(err); code == Error.Unauthorized {
+		writer.Header().Set("WWW-Authenticate", challenge)
+	}
+	HandleError(writer, request, err, handler)
+}
+
+// quoteAuthParam quotes value as an auth-param quoted-string.
+func quoteAuthParam(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
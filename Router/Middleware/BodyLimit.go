@@ -0,0 +1,105 @@
+// Package Middleware provides request body size limiting and content-type enforcement.
+// Wraps every request body in http.MaxBytesReader so handlers cannot forget to.
+package Middleware
+
+import (
+	"LiteFrame/Router/Error"
+	"LiteFrame/Router/Param"
+	"LiteFrame/Router/Types"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// DefaultMaxBodyBytes is the body size limit used by NewBodyLimit (1 MiB).
+const DefaultMaxBodyBytes = 1 << 20
+
+// BodyLimit is a middleware that limits request body size and checks Content-Type.
+//
+// Requests declaring a larger Content-Length are rejected with 413 before the handler runs.
+// Other bodies are wrapped in http.MaxBytesReader; a handler reading past the limit gets an
+// *http.MaxBytesError, which error-returning handlers can simply return: the central error
+// responder (Error.WriteError) reports it as 413.
+//
+// When ContentTypes is set, requests with a body whose media type is not listed are
+// rejected with 415. Entries ending in "/" match every subtype.
+//
+// Routes overrides the limit per route pattern; register a separate BodyLimit on a
+// Tree.Group for group-wide limits.
+//
+// Usage example:
+//
+//	limit := Middleware.NewBodyLimit(64 << 10)
+//	limit.ContentTypes = []string{"application/json"}
+//	limit.Routes = map[string]int64{"/uploads": 50 << 20}
+//	tree.SetMiddleware(limit)
+type BodyLimit struct {
+	MaxBytes     int64                  // Default body size limit in bytes (non-positive: unlimited)
+	Routes       map[string]int64       // Per-pattern limit overrides
+	ContentTypes []string               // Accepted media types for requests with a body (nil: any)
+	ErrorHandler Types.ErrorHandlerFunc // Writes 413 and 415 responses (nil: the tree's, see HandleError)
+}
+
+// NewBodyLimit creates a BodyLimit middleware with the given limit (DefaultMaxBodyBytes if 0).
+func NewBodyLimit(maxBytes int64) *BodyLimit {
+	if maxBytes == 0 {
+		maxBytes = DefaultMaxBodyBytes
+	}
+	return &BodyLimit{
+		MaxBytes: maxBytes,
+	}
+}
+
+// LimitFor returns the body size limit applied to requests matching pattern.
+func (instance *BodyLimit) LimitFor(pattern string) int64 {
+	if limit, ok := instance.Routes[pattern]; ok {
+		return limit
+	}
+	return instance.MaxBytes
+}
+
+// GetHandler implements the Middleware interface.
+func (instance *BodyLimit) GetHandler() MiddleWareFunc {
+	return func(next Types.HandlerFunc) Types.HandlerFunc {
+		return func(writer http.ResponseWriter, request *http.Request, params *Param.Params) {
+			if !hasBody(request) {
+				next(writer, request, params)
+				return
+			}
+			if len(instance.ContentTypes) > 0 && !instance.acceptsType(request.Header.Get("Content-Type")) {
+				HandleError(writer, request, Error.NewErrorWithCode(Error.UnsupportedMediaType, request.URL.Path), instance.ErrorHandler)
+				return
+			}
+			limit := instance.LimitFor(request.Pattern)
+			if limit > 0 {
+				if request.ContentLength > limit {
+					HandleError(writer, request, Error.NewErrorWithCode(Error.RequestTooLarge, request.URL.Path), instance.ErrorHandler)
+					return
+				}
+				request.Body = http.MaxBytesReader(writer, request.Body, limit)
+			}
+			next(writer, request, params)
+		}
+	}
+}
+
+// hasBody reports whether request carries a body.
+// Content-Length is -1 for chunked bodies of unknown length.
+func hasBody(request *http.Request) bool {
+	return request.Body != nil && request.Body != http.NoBody && request.ContentLength != 0
+}
+
+// acceptsType reports whether contentType matches ContentTypes.
+func (instance *BodyLimit) acceptsType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, allowed := range instance.ContentTypes {
+		allowed = strings.ToLower(allowed)
+		if mediaType == allowed || (strings.HasSuffix(allowed, "/") && strings.HasPrefix(mediaType, allowed)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,220 @@
+// Package Middleware provides Cross-Origin Resource Sharing (CORS) middleware.
+// Answers preflight requests with the methods actually registered for the requested route.
+package Middleware
+
+import (
+	"LiteFrame/Router/Param"
+	"LiteFrame/Router/Types"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MethodLister reports the HTTP methods registered for a path.
+// Implemented by Tree.Tree; lets CORS answer preflights without a static method list.
+type MethodLister interface {
+	AllowedMethods(path string) []string
+}
+
+// CORS is a middleware implementing the Fetch standard's CORS protocol.
+//
+// AllowedOrigins entries are matched case-insensitively and may be:
+//   - "*": any origin
+//   - an exact origin: "https://app.example.com"
+//   - a wildcard subdomain: "https://*.example.com" (matches any subdomain, not the apex)
+//
+// Origins admitted only by "*" always get a literal "*" without Access-Control-Allow-Credentials,
+// so AllowCredentials never exposes credentialed responses to arbitrary sites. Credentials are
+// only sent to origins matched by an exact or wildcard entry, or by AllowOriginFunc.
+//
+// Preflight requests (OPTIONS with Origin and Access-Control-Request-Method) are answered
+// directly with 204. Allowed methods come from Methods when set, so the response lists exactly
+// the methods registered on the matched route; preflights for unknown routes are passed to
+// the next handler (usually resulting in 404).
+//
+// Usage example:
+//
+//	cors := Middleware.NewCORS(tree)
+//	cors.AllowedOrigins = []string{"https://*.example.com"}
+//	cors.AllowCredentials = true
+//	tree.SetMiddleware(cors)
+type CORS struct {
+	AllowedOrigins   []string                                        // Allowed origins (exact, "*" or wildcard subdomain)
+	AllowOriginFunc  func(origin string, request *http.Request) bool // Additional origin check (nil: none)
+	AllowedMethods   []string                                        // Methods used when Methods is nil
+	AllowedHeaders   []string                                        // Allowed request headers (nil: reflect the requested headers)
+	ExposedHeaders   []string                                        // Response headers exposed to scripts
+	AllowCredentials bool                                            // Whether to send Access-Control-Allow-Credentials (not for "*")
+	MaxAge           time.Duration                                   // Preflight cache duration (0: header omitted)
+	Methods          MethodLister                                    // Source of per-route methods (nil: AllowedMethods)
+}
+
+// DefaultCORSMethods is the method list used when neither Methods nor AllowedMethods is set.
+var DefaultCORSMethods = []string{http.MethodGet, http.MethodHead, http.MethodPost}
+
+// NewCORS creates a CORS middleware that allows any origin without credentials
+// and answers preflights with the methods registered in methods (may be nil).
+func NewCORS(methods MethodLister) *CORS {
+	return &CORS{
+		AllowedOrigins: []string{"*"},
+		Methods:        methods,
+	}
+}
+
+// originMatcher is the compiled form of AllowedOrigins.
+type originMatcher struct {
+	any       bool
+	exact     map[string]struct{}
+	wildcards [][2]string // scheme+"://" prefix and "."+domain suffix
+}
+
+// compileOrigins lowercases and classifies the configured origins.
+func compileOrigins(origins []string) *originMatcher {
+	matcher := &originMatcher{exact: make(map[string]struct{}, len(origins))}
+	for _, origin := range origins {
+		origin = strings.ToLower(origin)
+		switch {
+		case origin == "*":
+			matcher.any = true
+		case strings.Contains(origin, "://*."):
+			index := strings.Index(origin, "*")
+			matcher.wildcards = append(matcher.wildcards, [2]string{origin[:index], origin[index+1:]})
+		default:
+			matcher.exact[origin] = struct{}{}
+		}
+	}
+	return matcher
+}
+
+// match reports whether origin matches a configured exact or wildcard origin.
+func (instance *originMatcher) match(origin string) bool {
+	origin = strings.ToLower(origin)
+	if _, ok := instance.exact[origin]; ok {
+		return true
+	}
+	for _, wildcard := range instance.wildcards {
+		if len(origin) > len(wildcard[0])+len(wildcard[1]) &&
+			strings.HasPrefix(origin, wildcard[0]) && strings.HasSuffix(origin, wildcard[1]) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetHandler implements the Middleware interface.
+func (instance *CORS) GetHandler() MiddleWareFunc {
+	origins := compileOrigins(instance.AllowedOrigins)
+	// Only a bare "*" answers every origin the same way
+	varies := !origins.any || len(origins.exact) > 0 || len(origins.wildcards) > 0 || instance.AllowOriginFunc != nil
+	exposed := strings.Join(instance.ExposedHeaders, ", ")
+	maxAge := ""
+	if instance.MaxAge > 0 {
+		maxAge = strconv.FormatInt(int64(instance.MaxAge/time.Second), 10)
+	}
+	allowedHeaders := make([]string, len(instance.AllowedHeaders))
+	for index, header := range instance.AllowedHeaders {
+		allowedHeaders[index] = http.CanonicalHeaderKey(header)
+	}
+
+	return func(next Types.HandlerFunc) Types.HandlerFunc {
+		return func(writer http.ResponseWriter, request *http.Request, params *Param.Params) {
+			origin := request.Header.Get("Origin")
+			header := writer.Header()
+			preflight := origin != "" && request.Method == http.MethodOptions &&
+				request.Header.Get("Access-Control-Request-Method") != ""
+			if preflight {
+				header.Add("Vary", "Origin")
+				header.Add("Vary", "Access-Control-Request-Method")
+				header.Add("Vary", "Access-Control-Request-Headers")
+			} else if varies {
+				// The response depends on Origin even when it is absent
+				header.Add("Vary", "Origin")
+			}
+			if origin == "" {
+				next(writer, request, params)
+				return
+			}
+
+			explicit := origins.match(origin) ||
+				(instance.AllowOriginFunc != nil && instance.AllowOriginFunc(origin, request))
+			allowed := origins.any || explicit
+			if !preflight {
+				if allowed {
+					instance.setOrigin(header, origin, explicit)
+					if exposed != "" {
+						header.Set("Access-Control-Expose-Headers", exposed)
+					}
+				}
+				next(writer, request, params)
+				return
+			}
+
+			methods := instance.methods(request.URL.Path)
+			if methods == nil {
+				next(writer, request, params)
+				return
+			}
+			if allowed && slices.Contains(methods, request.Header.Get("Access-Control-Request-Method")) {
+				if requested, ok := instance.allowHeaders(request.Header.Values("Access-Control-Request-Headers"), allowedHeaders); ok {
+					instance.setOrigin(header, origin, explicit)
+					header.Set("Access-Control-Allow-Methods", strings.Join(methods, ", "))
+					if requested != "" {
+						header.Set("Access-Control-Allow-Headers", requested)
+					}
+					if maxAge != "" {
+						header.Set("Access-Control-Max-Age", maxAge)
+					}
+				}
+			}
+			writer.WriteHeader(http.StatusNoContent)
+		}
+	}
+}
+
+// setOrigin writes Access-Control-Allow-Origin and Access-Control-Allow-Credentials.
+// Origins that were not explicitly allowed get "*", which browsers never combine with credentials.
+func (instance *CORS) setOrigin(header http.Header, origin string, explicit bool) {
+	if !explicit {
+		header.Set("Access-Control-Allow-Origin", "*")
+		return
+	}
+	header.Set("Access-Control-Allow-Origin", origin)
+	if instance.AllowCredentials {
+		header.Set("Access-Control-Allow-Credentials", "true")
+	}
+}
+
+// methods returns the methods allowed for path.
+func (instance *CORS) methods(path string) []string {
+	if instance.Methods != nil {
+		return instance.Methods.AllowedMethods(path)
+	}
+	if instance.AllowedMethods != nil {
+		return instance.AllowedMethods
+	}
+	return DefaultCORSMethods
+}
+
+// allowHeaders checks the comma separated Access-Control-Request-Headers values against allowed.
+// Returns the value for Access-Control-Allow-Headers and whether every requested header is allowed.
+// With a nil allow list the requested headers are reflected.
+func (instance *CORS) allowHeaders(values []string, allowed []string) (string, bool) {
+	requested := make([]string, 0, len(values))
+	for _, value := range values {
+		for _, name := range strings.Split(value, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				requested = append(requested, http.CanonicalHeaderKey(name))
+			}
+		}
+	}
+	if instance.AllowedHeaders != nil {
+		for _, name := range requested {
+			if !slices.Contains(allowed, name) {
+				return "", false
+			}
+		}
+	}
+	return strings.Join(requested, ", "), true
+}
@@ -0,0 +1,220 @@
+// Package Middleware provides cross-site request forgery protection.
+// Rejects cross-origin state-changing requests using Sec-Fetch-Site and Origin, and requires
+// a token from a double-submit cookie or a server-side (synchronizer) secret.
+package Middleware
+
+import (
+	"LiteFrame/Router/Error"
+	"LiteFrame/Router/Param"
+	"LiteFrame/Router/Types"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// CSRF defaults used by NewCSRF.
+const (
+	DefaultCSRFCookieName = "_csrf"        // Cookie holding the double-submit secret
+	DefaultCSRFHeaderName = "X-CSRF-Token" // Request header carrying the token
+	DefaultCSRFFieldName  = "csrf_token"   // Form field carrying the token
+	csrfSecretLength      = 32             // Random bytes of a generated secret
+)
+
+// CSRFTokenKey is an empty structure for identifying the CSRF token in context.
+// Used as a key for token storage in context.WithValue.
+type CSRFTokenKey struct{}
+
+// GetCSRFToken extracts the CSRF token to embed in forms or send from scripts.
+// Returns an empty string if the request has no CSRF secret.
+//
+// Usage example:
+//
+//	<input type="hidden" name="csrf_token" value="{{ .CSRFToken }}">  // Middleware.GetCSRFToken(ctx)
+func GetCSRFToken(ctx context.Context) string {
+	token, _ := ctx.Value(CSRFTokenKey{}).(string)
+	return token
+}
+
+// CSRF is a middleware that protects unsafe methods (everything except GET, HEAD, OPTIONS
+// and TRACE) against cross-site request forgery. Rejected requests get 403.
+//
+// Unsafe requests pass two checks:
+//   - Origin: a Sec-Fetch-Site other than "same-origin" or "none" is rejected; browsers
+//     without it are checked by comparing Origin to the request's scheme and host.
+//     TrustedOrigins (exact or "https://*.example.com") are always accepted.
+//   - Token: the X-CSRF-Token header or the csrf_token form field must match the secret.
+//
+// The secret is a random value in a cookie (double-submit cookie) or, when Secret is set,
+// a value kept server side such as in the session (synchronizer token). The token exposed
+// through GetCSRFToken is masked with fresh random bytes on every request, so it does not
+// repeat in compressed responses (BREACH); the raw cookie value is accepted as well.
+//
+// Usage example:
+//
+//	pages := tree.Group("/", Middleware.NewCSRF())
+//	pages.SetHandler(Tree.GET, "/profile", func(w http.ResponseWriter, r *http.Request, params *Param.Params) {
+//	    render(w, "profile.html", Middleware.GetCSRFToken(r.Context()))
+//	})
+type CSRF struct {
+	CookieName     string                                      // Secret cookie name
+	CookiePath     string                                      // Secret cookie path
+	CookieDomain   string                                      // Secret cookie domain ("": host only)
+	CookieMaxAge   time.Duration                               // Secret cookie lifetime (0: session cookie)
+	CookieSecure   bool                                        // Whether the cookie is HTTPS only
+	CookieSameSite http.SameSite                               // Secret cookie SameSite attribute
+	HeaderName     string                                      // Token request header
+	FieldName      string                                      // Token form field
+	TrustedOrigins []string                                    // Cross origins allowed to send unsafe requests
+	Secret         func(request *http.Request) (string, error) // Server-side secret (nil: double-submit cookie)
+	ErrorHandler   Types.ErrorHandlerFunc                      // Writes rejection responses (nil: the tree's, see HandleError)
+}
+
+// NewCSRF creates a CSRF middleware using a Secure, SameSite=Lax double-submit cookie.
+func NewCSRF() *CSRF {
+	return &CSRF{
+		CookieName:     DefaultCSRFCookieName,
+		CookiePath:     "/",
+		CookieMaxAge:   12 * time.Hour,
+		CookieSecure:   true,
+		CookieSameSite: http.SameSiteLaxMode,
+		HeaderName:     DefaultCSRFHeaderName,
+		FieldName:      DefaultCSRFFieldName,
+	}
+}
+
+// GetHandler implements the Middleware interface.
+func (instance *CSRF) GetHandler() MiddleWareFunc {
+	trusted := compileOrigins(instance.TrustedOrigins)
+
+	return func(next Types.HandlerFunc) Types.HandlerFunc {
+		return func(writer http.ResponseWriter, request *http.Request, params *Param.Params) {
+			secret, err := instance.secret(writer, request)
+			if err != nil {
+				HandleError(writer, request, fmt.Errorf("csrf secret: %w", err), instance.ErrorHandler)
+				return
+			}
+			if len(secret) > 0 {
+				request = request.WithContext(context.WithValue(request.Context(), CSRFTokenKey{}, maskCSRFToken(secret)))
+			}
+			if isSafeMethod(request.Method) {
+				next(writer, request, params)
+				return
+			}
+			if !sameOrigin(request, trusted) || !instance.validToken(request, secret) {
+				HandleError(writer, request, Error.NewErrorWithCode(Error.Forbidden, request.URL.Path), instance.ErrorHandler)
+				return
+			}
+			next(writer, request, params)
+		}
+	}
+}
+
+// secret returns the request's CSRF secret, issuing a new cookie if it has none.
+func (instance *CSRF) secret(writer http.ResponseWriter, request *http.Request) ([]byte, error) {
+	if instance.Secret != nil {
+		secret, err := instance.Secret(request)
+		return []byte(secret), err
+	}
+	name := instance.CookieName
+	if name == "" {
+		name = DefaultCSRFCookieName
+	}
+	if cookie, err := request.Cookie(name); err == nil {
+		if secret, err := base64.RawURLEncoding.DecodeString(cookie.Value); err == nil && len(secret) == csrfSecretLength {
+			return secret, nil
+		}
+	}
+	secret := make([]byte, csrfSecretLength)
+	_, _ = rand.Read(secret)
+	cookie := &http.Cookie{
+		Name:     name,
+		Value:    base64.RawURLEncoding.EncodeToString(secret),
+		Path:     instance.CookiePath,
+		Domain:   instance.CookieDomain,
+		Secure:   instance.CookieSecure,
+		SameSite: instance.CookieSameSite,
+	}
+	if instance.CookieMaxAge > 0 {
+		cookie.MaxAge = int(instance.CookieMaxAge / time.Second)
+	}
+	http.SetCookie(writer, cookie)
+	// A fresh secret cannot match any submitted token, so unsafe requests without a cookie fail
+	return secret, nil
+}
+
+// validToken reports whether the submitted token matches secret.
+func (instance *CSRF) validToken(request *http.Request, secret []byte) bool {
+	if len(secret) == 0 {
+		return false
+	}
+	headerName := instance.HeaderName
+	if headerName == "" {
+		headerName = DefaultCSRFHeaderName
+	}
+	token := request.Header.Get(headerName)
+	if token == "" {
+		fieldName := instance.FieldName
+		if fieldName == "" {
+			fieldName = DefaultCSRFFieldName
+		}
+		token = request.PostFormValue(fieldName)
+	}
+	submitted, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return false
+	}
+	if len(submitted) == 2*len(secret) {
+		submitted = unmaskCSRFToken(submitted)
+	}
+	return subtle.ConstantTimeCompare(submitted, secret) == 1
+}
+
+// isSafeMethod reports whether method is safe as defined by RFC 9110.
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+	return false
+}
+
+// sameOrigin reports whether request was sent by its own origin or a trusted one.
+// Requests without Sec-Fetch-Site and Origin (non-browser clients) pass.
+func sameOrigin(request *http.Request, trusted *originMatcher) bool {
+	origin := request.Header.Get("Origin")
+	if origin != "" && origin != "null" && trusted.match(origin) {
+		return true
+	}
+	switch request.Header.Get("Sec-Fetch-Site") {
+	case "same-origin", "none":
+		return true
+	case "":
+	default:
+		return false
+	}
+	if origin == "" {
+		return true
+	}
+	return strings.EqualFold(origin, RequestScheme(request)+"://"+request.Host)
+}
+
+// maskCSRFToken returns secret XORed with a random one-time pad, prefixed by the pad.
+func maskCSRFToken(secret []byte) string {
+	token := make([]byte, 2*len(secret))
+	_, _ = rand.Read(token[:len(secret)])
+	subtle.XORBytes(token[len(secret):], token[:len(secret)], secret)
+	return base64.RawURLEncoding.EncodeToString(token)
+}
+
+// unmaskCSRFToken reverses maskCSRFToken.
+func unmaskCSRFToken(token []byte) []byte {
+	half := len(token) / 2
+	secret := make([]byte, half)
+	subtle.XORBytes(secret, token[:half], token[half:])
+	return secret
+}
@@ -0,0 +1,366 @@
+// Package Middleware provides response compression middleware.
+// Negotiates gzip or deflate with Accept-Encoding and compresses eligible responses.
+package Middleware
+
+import (
+	"LiteFrame/Router/Param"
+	"LiteFrame/Router/Types"
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"errors"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Content coding names supported by Compress.
+const (
+	EncodingGzip     = "gzip"
+	EncodingDeflate  = "deflate"
+	EncodingIdentity = "identity"
+)
+
+// ErrHijackCompressed is returned by Hijack on a response Compress has started to buffer or compress.
+var ErrHijackCompressed = errors.New("cannot hijack a compressed response")
+
+// DefaultCompressMinSize is the smallest response body compressed by NewCompress.
+// Smaller bodies usually grow or gain too little to justify the CPU cost.
+const DefaultCompressMinSize = 1024
+
+// DefaultCompressTypes is the content-type allowlist used by NewCompress.
+// Entries ending in "/" match every subtype.
+var DefaultCompressTypes = []string{
+	"text/",
+	"application/json",
+	"application/problem+json",
+	"application/javascript",
+	"application/xml",
+	"image/svg+xml",
+}
+
+// Compress is a middleware that compresses responses with gzip or deflate.
+//
+// The encoding is negotiated from Accept-Encoding q-values; gzip wins ties.
+// A response is compressed only if all of the following hold:
+//   - the request is not HEAD and the status allows a body (not 1xx, 204, 206 or 304)
+//   - the handler did not set Content-Encoding itself
+//   - the content type (set or sniffed) matches ContentTypes
+//   - the body is at least MinSize bytes and not empty (decided after buffering up to MinSize bytes)
+//
+// Vary: Accept-Encoding is added to every eligible response, compressed or not, so caches
+// keep the encodings apart; this includes requests that negotiated identity, whose body is
+// passed through without buffering. Strong ETags of compressed responses are weakened.
+//
+// The writer passed downstream implements http.Flusher, http.Hijacker, io.ReaderFrom and
+// http.Pusher only if the underlying writer does. Hijacking fails with ErrHijackCompressed
+// once compressed or buffered body bytes exist, as they cannot be handed over with the connection.
+//
+// Usage example:
+//
+//	compress := Middleware.NewCompress()
+//	compress.Level = gzip.BestSpeed
+//	tree.SetMiddleware(compress)
+type Compress struct {
+	Level        int      // Compression level for both encodings (flate.DefaultCompression if 0)
+	MinSize      int      // Minimum body size to compress in bytes
+	ContentTypes []string // Compressible media types (prefixes ending in "/" match all subtypes)
+}
+
+// NewCompress creates a Compress middleware with default level, size threshold and content types.
+func NewCompress() *Compress {
+	return &Compress{
+		Level:        flate.DefaultCompression,
+		MinSize:      DefaultCompressMinSize,
+		ContentTypes: DefaultCompressTypes,
+	}
+}
+
+// NegotiateEncoding selects gzip, deflate or identity from an Accept-Encoding header value.
+// Codings with q=0 are refused; "*" applies to codings not listed explicitly.
+// Returns identity when neither gzip nor deflate is acceptable.
+func NegotiateEncoding(acceptEncoding string) string {
+	gzipQ, deflateQ, wildcardQ := -1.0, -1.0, -1.0
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, parameters, _ := strings.Cut(strings.TrimSpace(part), ";")
+		quality := 1.0
+		if parameters != "" {
+			key, value, found := strings.Cut(strings.TrimSpace(parameters), "=")
+			if found && strings.EqualFold(strings.TrimSpace(key), "q") {
+				parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+				if err != nil {
+					continue
+				}
+				quality = parsed
+			}
+		}
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case EncodingGzip, "x-gzip":
+			gzipQ = quality
+		case EncodingDeflate:
+			deflateQ = quality
+		case "*":
+			wildcardQ = quality
+		}
+	}
+	if gzipQ < 0 {
+		gzipQ = wildcardQ
+	}
+	if deflateQ < 0 {
+		deflateQ = wildcardQ
+	}
+	switch {
+	case gzipQ > 0 && gzipQ >= deflateQ:
+		return EncodingGzip
+	case deflateQ > 0:
+		return EncodingDeflate
+	default:
+		return EncodingIdentity
+	}
+}
+
+// GetHandler implements the Middleware interface.
+func (instance *Compress) GetHandler() MiddleWareFunc {
+	level := instance.Level
+	if level == 0 {
+		level = flate.DefaultCompression
+	}
+	pools := map[string]*sync.Pool{
+		EncodingGzip: {New: func() any {
+			writer, _ := gzip.NewWriterLevel(io.Discard, level)
+			return writer
+		}},
+		EncodingDeflate: {New: func() any {
+			writer, _ := flate.NewWriter(io.Discard, level)
+			return writer
+		}},
+	}
+
+	return func(next Types.HandlerFunc) Types.HandlerFunc {
+		return func(writer http.ResponseWriter, request *http.Request, params *Param.Params) {
+			encoding := NegotiateEncoding(request.Header.Get("Accept-Encoding"))
+			// Identity responses still pass through compressWriter, which adds Vary when eligible
+			compressor := &compressWriter{
+				ResponseWriter: writer,
+				config:         instance,
+				pool:           pools[encoding],
+				encoding:       encoding,
+				head:           request.Method == http.MethodHead,
+				status:         http.StatusOK,
+			}
+			// On panic the buffered body is dropped so an outer Recovery can still respond
+			defer compressor.release()
+			_, wrapped := wrapResponseWriter(compressor, writer)
+			next(wrapped, request, params)
+			compressor.close()
+		}
+	}
+}
+
+// compressible reports whether contentType matches the allowlist.
+func (instance *Compress) compressible(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, allowed := range instance.ContentTypes {
+		if mediaType == allowed || (strings.HasSuffix(allowed, "/") && strings.HasPrefix(mediaType, allowed)) {
+			return true
+		}
+	}
+	return false
+}
+
+// compressWriter buffers the start of the body until it can decide whether to compress.
+// It implements every optional interface; wrapResponseWriter exposes only those of the
+// underlying writer, so the assertions on it below cannot fail.
+type compressWriter struct {
+	http.ResponseWriter
+	config      *Compress
+	pool        *sync.Pool
+	encoding    string
+	head        bool
+	status      int
+	wroteHeader bool           // Handler called WriteHeader
+	decided     bool           // Headers have been sent downstream
+	buffer      []byte         // Body bytes written before the decision
+	writer      io.WriteCloser // Active compressor (nil: identity)
+}
+
+// resetter is implemented by gzip.Writer and flate.Writer.
+type resetter interface {
+	io.WriteCloser
+	Reset(io.Writer)
+	Flush() error
+}
+
+// WriteHeader records the status; headers are sent once the encoding is decided.
+func (instance *compressWriter) WriteHeader(status int) {
+	if instance.decided || instance.wroteHeader {
+		return
+	}
+	if status >= 100 && status < 200 && status != http.StatusSwitchingProtocols {
+		instance.ResponseWriter.WriteHeader(status)
+		return
+	}
+	instance.status = status
+	instance.wroteHeader = true
+	if !instance.bodyAllowed() {
+		instance.decide(false)
+	}
+}
+
+// Write buffers until MinSize bytes are available, then streams through the chosen encoding.
+func (instance *compressWriter) Write(data []byte) (int, error) {
+	if !instance.decided {
+		if length := instance.Header().Get("Content-Length"); length != "" {
+			if size, err := strconv.Atoi(length); err == nil && size < instance.config.MinSize {
+				instance.decide(false)
+			}
+		}
+	}
+	if !instance.decided {
+		instance.buffer = append(instance.buffer, data...)
+		// Identity only needs the first bytes to sniff the content type
+		if len(instance.buffer) == 0 || (instance.encoding != EncodingIdentity && !instance.large()) {
+			return len(data), nil
+		}
+		instance.decide(instance.large())
+		if err := instance.flushBuffer(); err != nil {
+			return 0, err
+		}
+		return len(data), nil
+	}
+	if instance.writer != nil {
+		return instance.writer.Write(data)
+	}
+	return instance.ResponseWriter.Write(data)
+}
+
+// Flush decides with the data buffered so far and flushes the compressor and the connection.
+func (instance *compressWriter) Flush() {
+	if !instance.decided {
+		instance.decide(instance.large())
+		_ = instance.flushBuffer()
+	}
+	if flusher, ok := instance.writer.(resetter); ok {
+		_ = flusher.Flush()
+	}
+	instance.ResponseWriter.(http.Flusher).Flush()
+}
+
+// Hijack hands the connection to the handler; nothing is compressed afterwards.
+// Fails with ErrHijackCompressed if body bytes are buffered or a compressed stream was started.
+func (instance *compressWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if instance.writer != nil || len(instance.buffer) > 0 {
+		return nil, nil, ErrHijackCompressed
+	}
+	conn, buffer, err := instance.ResponseWriter.(http.Hijacker).Hijack()
+	if err == nil {
+		instance.decided = true
+	}
+	return conn, buffer, err
+}
+
+// ReadFrom copies source through Write, or straight to the underlying writer once the
+// response is known to stay uncompressed, keeping its sendfile/splice optimizations.
+func (instance *compressWriter) ReadFrom(source io.Reader) (int64, error) {
+	if instance.decided && instance.writer == nil {
+		return instance.ResponseWriter.(io.ReaderFrom).ReadFrom(source)
+	}
+	// Hide ReadFrom so io.Copy does not call back into it
+	return io.Copy(struct{ io.Writer }{instance}, source)
+}
+
+// Push initiates an HTTP/2 server push.
+func (instance *compressWriter) Push(target string, options *http.PushOptions) error {
+	return instance.ResponseWriter.(http.Pusher).Push(target, options)
+}
+
+// Unwrap returns the underlying writer for http.ResponseController.
+func (instance *compressWriter) Unwrap() http.ResponseWriter {
+	return instance.ResponseWriter
+}
+
+// bodyAllowed reports whether the response can carry a compressible body.
+func (instance *compressWriter) bodyAllowed() bool {
+	switch instance.status {
+	case http.StatusNoContent, http.StatusPartialContent, http.StatusNotModified:
+		return false
+	}
+	return !instance.head
+}
+
+// large reports whether the buffered body reached MinSize. An empty body never does, so a
+// MinSize <= 0 cannot wrap an empty response in a compressed header and trailer.
+func (instance *compressWriter) large() bool {
+	return len(instance.buffer) > 0 && len(instance.buffer) >= instance.config.MinSize
+}
+
+// decide sends the headers, enabling compression if large is true and the response is eligible.
+// Responses that negotiated identity are never compressed but still get Vary.
+func (instance *compressWriter) decide(large bool) {
+	instance.decided = true
+	header := instance.Header()
+	if header.Get("Content-Type") == "" && len(instance.buffer) > 0 {
+		header.Set("Content-Type", http.DetectContentType(instance.buffer))
+	}
+	eligible := header.Get("Content-Encoding") == "" && instance.config.compressible(header.Get("Content-Type"))
+	if eligible {
+		header.Add("Vary", "Accept-Encoding")
+	}
+	if eligible && large && instance.bodyAllowed() && instance.encoding != EncodingIdentity {
+		header.Set("Content-Encoding", instance.encoding)
+		header.Del("Content-Length")
+		if etag := header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+			header.Set("ETag", "W/"+etag)
+		}
+		compressor := instance.pool.Get().(resetter)
+		compressor.Reset(instance.ResponseWriter)
+		instance.writer = compressor
+	}
+	instance.ResponseWriter.WriteHeader(instance.status)
+}
+
+// flushBuffer writes the buffered body through the chosen encoding.
+func (instance *compressWriter) flushBuffer() error {
+	if len(instance.buffer) == 0 {
+		return nil
+	}
+	buffer := instance.buffer
+	instance.buffer = nil
+	if instance.writer != nil {
+		_, err := instance.writer.Write(buffer)
+		return err
+	}
+	_, err := instance.ResponseWriter.Write(buffer)
+	return err
+}
+
+// close finishes the response after the handler returns.
+func (instance *compressWriter) close() {
+	if !instance.decided {
+		if !instance.wroteHeader && len(instance.buffer) == 0 {
+			// Nothing written: let net/http send its implicit response
+			return
+		}
+		instance.decide(instance.large())
+		_ = instance.flushBuffer()
+	}
+	if instance.writer != nil {
+		_ = instance.writer.Close()
+	}
+}
+
+// release returns the compressor to the pool.
+func (instance *compressWriter) release() {
+	if instance.writer != nil {
+		instance.writer.(resetter).Reset(io.Discard)
+		instance.pool.Put(instance.writer)
+		instance.writer = nil
+	}
+}
@@ -0,0 +1,292 @@
+// Package Middleware provides concurrency limiting and adaptive load shedding middleware.
+// Caps in-flight requests globally and per route, queues a bounded number of waiters by
+// priority and sheds the rest with 503 instead of letting latency collapse.
+package Middleware
+
+import (
+	"LiteFrame/Router/Error"
+	"LiteFrame/Router/Param"
+	"LiteFrame/Router/Types"
+	"context"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Priority is an enumeration of request priority classes.
+// Higher classes leave the wait queue first and may displace lower ones when it is full.
+type Priority uint32
+
+// Priority constants: Classes assigned per route through ConcurrencyLimit.Priorities.
+const (
+	PriorityLow      Priority = iota // Background and bulk traffic, shed first
+	PriorityNormal                   // Default class
+	PriorityCritical                 // Health checks, payments and other must-serve routes
+	priorityCount
+)
+
+// AIMD configures additive-increase/multiplicative-decrease adaptation of the global limit.
+// While completions stay below Target latency the limit grows by about one per limit's worth
+// of completions; a slower completion multiplies it by Decrease (at most once per Target).
+type AIMD struct {
+	Target   time.Duration // Latency above which the limit is decreased
+	Decrease float64       // Multiplicative decrease factor in (0, 1) (0: 0.9)
+	Min      int           // Lower bound of the limit (0: 1)
+	Max      int           // Upper bound of the limit (0: the initial Limit)
+}
+
+// ConcurrencyLimit is a middleware that caps the number of requests handled at once.
+//
+// A request first acquires a slot of its route's limiter (Routes, keyed by request.Pattern),
+// then of the global limiter. When no slot is free it waits in a bounded queue for at most
+// QueueTimeout; when the queue is full, the timeout expires or the client goes away, the
+// request is shed through ErrorHandler with 503.
+//
+// Usage example:
+//
+//	limiter := Middleware.NewConcurrencyLimit(200)
+//	limiter.Routes = map[string]int{"/reports/:id": 4}
+//	limiter.Priorities = map[string]Middleware.Priority{"/health": Middleware.PriorityCritical}
+//	limiter.Adaptive = &Middleware.AIMD{Target: 250 * time.Millisecond, Min: 20}
+//	tree.SetMiddleware(limiter)
+type ConcurrencyLimit struct {
+	Limit        int                    // Global in-flight cap (non-positive: unlimited)
+	Routes       map[string]int         // Per-pattern in-flight caps
+	Priorities   map[string]Priority    // Per-pattern priority class (default PriorityNormal)
+	QueueSize    int                    // Maximum number of waiting requests per limiter (0: shed immediately)
+	QueueTimeout time.Duration          // Maximum time spent waiting for a slot (0: until the request is canceled)
+	Adaptive     *AIMD                  // Adapts the global limit to latency (nil: fixed limit)
+	ErrorHandler Types.ErrorHandlerFunc // Writes the shed response (nil: the tree's, see HandleError)
+
+	once   sync.Once                      // Guards creation of the limiters
+	global *concurrencyLimiter            // Global limiter (nil: unlimited)
+	routes map[string]*concurrencyLimiter // Per-pattern limiters
+}
+
+// NewConcurrencyLimit creates a ConcurrencyLimit middleware with the given global cap,
+// a queue as large as the cap and a one second queue timeout.
+func NewConcurrencyLimit(limit int) *ConcurrencyLimit {
+	return &ConcurrencyLimit{
+		Limit:        limit,
+		QueueSize:    limit,
+		QueueTimeout: time.Second,
+	}
+}
+
+// CurrentLimit returns the global limit in effect, which changes over time in adaptive mode.
+// Returns 0 when there is no global limit.
+func (instance *ConcurrencyLimit) CurrentLimit() int {
+	instance.once.Do(instance.init)
+	if instance.global == nil {
+		return 0
+	}
+	instance.global.mutex.Lock()
+	defer instance.global.mutex.Unlock()
+	return instance.global.current()
+}
+
+// init creates the limiters from the configuration on first use.
+// Limiters belong to the instance, so registering it on several routes or groups
+// shares one set of caps between them.
+func (instance *ConcurrencyLimit) init() {
+	if instance.Limit > 0 {
+		instance.global = newConcurrencyLimiter(instance.Limit, instance.QueueSize, instance.Adaptive)
+	}
+	instance.routes = make(map[string]*concurrencyLimiter, len(instance.Routes))
+	for pattern, limit := range instance.Routes {
+		if limit > 0 {
+			instance.routes[pattern] = newConcurrencyLimiter(limit, instance.QueueSize, nil)
+		}
+	}
+}
+
+// GetHandler implements the Middleware interface.
+// The configuration is read on the first call; later changes have no effect.
+func (instance *ConcurrencyLimit) GetHandler() MiddleWareFunc {
+	instance.once.Do(instance.init)
+	global, routes := instance.global, instance.routes
+
+	return func(next Types.HandlerFunc) Types.HandlerFunc {
+		return func(writer http.ResponseWriter, request *http.Request, params *Param.Params) {
+			priority, ok := instance.Priorities[request.Pattern]
+			if !ok {
+				priority = PriorityNormal
+			}
+			priority = min(priority, PriorityCritical)
+			ctx := request.Context()
+			if instance.QueueTimeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, instance.QueueTimeout)
+				defer cancel()
+			}
+
+			route := routes[request.Pattern]
+			if route != nil {
+				if !route.acquire(ctx, priority) {
+					instance.shed(writer, request)
+					return
+				}
+				defer route.release(0)
+			}
+			if global != nil {
+				if !global.acquire(ctx, priority) {
+					instance.shed(writer, request)
+					return
+				}
+				start := time.Now()
+				defer func() { global.release(time.Since(start)) }()
+			}
+			next(writer, request, params)
+		}
+	}
+}
+
+// shed rejects request with an Overloaded error.
+func (instance *ConcurrencyLimit) shed(writer http.ResponseWriter, request *http.Request) {
+	writer.Header().Set("Retry-After", "1")
+	HandleError(writer, request, Error.NewErrorWithCode(Error.Overloaded, request.URL.Path), instance.ErrorHandler)
+}
+
+// concurrencyLimiter is a counting semaphore with a bounded priority queue.
+type concurrencyLimiter struct {
+	mutex        sync.Mutex
+	limit        float64 // Current limit (fractional in adaptive mode)
+	inFlight     int
+	queueSize    int
+	queues       [priorityCount][]*concurrencyWaiter
+	queued       int
+	adaptive     *AIMD
+	minimum      float64 // Adaptive lower bound
+	maximum      float64 // Adaptive upper bound
+	lastDecrease time.Time
+}
+
+// concurrencyWaiter is a queued request; ready is closed once it is granted or displaced.
+type concurrencyWaiter struct {
+	ready    chan struct{}
+	priority Priority
+	granted  bool
+}
+
+// newConcurrencyLimiter creates a limiter; adaptive may be nil.
+func newConcurrencyLimiter(limit int, queueSize int, adaptive *AIMD) *concurrencyLimiter {
+	limiter := &concurrencyLimiter{limit: float64(limit), queueSize: queueSize, adaptive: adaptive}
+	if adaptive != nil {
+		limiter.minimum = float64(max(adaptive.Min, 1))
+		limiter.maximum = float64(adaptive.Max)
+		if adaptive.Max <= 0 {
+			limiter.maximum = float64(limit)
+		}
+		limiter.maximum = math.Max(limiter.maximum, limiter.minimum)
+	}
+	return limiter
+}
+
+// current returns the integer limit; the mutex must be held.
+func (instance *concurrencyLimiter) current() int {
+	return max(int(instance.limit), 1)
+}
+
+// acquire takes a slot, waiting in the queue until ctx is done. Reports whether a slot was taken.
+func (instance *concurrencyLimiter) acquire(ctx context.Context, priority Priority) bool {
+	instance.mutex.Lock()
+	if instance.inFlight < instance.current() && instance.queued == 0 {
+		instance.inFlight++
+		instance.mutex.Unlock()
+		return true
+	}
+	if instance.queued >= instance.queueSize && !instance.displace(priority) {
+		instance.mutex.Unlock()
+		return false
+	}
+	waiter := &concurrencyWaiter{ready: make(chan struct{}), priority: priority}
+	instance.queues[priority] = append(instance.queues[priority], waiter)
+	instance.queued++
+	instance.mutex.Unlock()
+
+	select {
+	case <-waiter.ready:
+		return waiter.granted
+	case <-ctx.Done():
+		instance.mutex.Lock()
+		defer instance.mutex.Unlock()
+		if waiter.granted {
+			// Granted concurrently with the cancellation; keep the slot
+			return true
+		}
+		instance.remove(waiter)
+		return false
+	}
+}
+
+// displace rejects the newest waiter of the lowest class below priority to make room.
+// The mutex must be held. Reports whether a waiter was displaced.
+func (instance *concurrencyLimiter) displace(priority Priority) bool {
+	for class := Priority(0); class < priority; class++ {
+		queue := instance.queues[class]
+		if len(queue) == 0 {
+			continue
+		}
+		victim := queue[len(queue)-1]
+		instance.queues[class] = queue[:len(queue)-1]
+		instance.queued--
+		close(victim.ready)
+		return true
+	}
+	return false
+}
+
+// remove deletes waiter from its queue; the mutex must be held.
+func (instance *concurrencyLimiter) remove(waiter *concurrencyWaiter) {
+	queue := instance.queues[waiter.priority]
+	for index, queued := range queue {
+		if queued == waiter {
+			instance.queues[waiter.priority] = append(queue[:index], queue[index+1:]...)
+			instance.queued--
+			return
+		}
+	}
+}
+
+// release frees a slot, adapts the limit to latency and grants slots to waiters by priority.
+func (instance *concurrencyLimiter) release(latency time.Duration) {
+	instance.mutex.Lock()
+	defer instance.mutex.Unlock()
+	instance.inFlight--
+	if instance.adaptive != nil {
+		instance.adapt(latency)
+	}
+	for class := priorityCount - 1; instance.inFlight < instance.current() && instance.queued > 0; {
+		queue := instance.queues[class]
+		if len(queue) == 0 {
+			class--
+			continue
+		}
+		waiter := queue[0]
+		instance.queues[class] = queue[1:]
+		instance.queued--
+		instance.inFlight++
+		waiter.granted = true
+		close(waiter.ready)
+	}
+}
+
+// adapt applies AIMD to the limit; the mutex must be held.
+func (instance *concurrencyLimiter) adapt(latency time.Duration) {
+	config := instance.adaptive
+	if latency > config.Target {
+		now := time.Now()
+		if now.Sub(instance.lastDecrease) < config.Target {
+			return
+		}
+		decrease := config.Decrease
+		if decrease <= 0 || decrease >= 1 {
+			decrease = 0.9
+		}
+		instance.limit = math.Max(instance.minimum, instance.limit*decrease)
+		instance.lastDecrease = now
+		return
+	}
+	instance.limit = math.Min(instance.maximum, instance.limit+1/instance.limit)
+}
@@ -0,0 +1,41 @@
+// Package Middleware provides the shared error response path of middleware.
+// Middleware rejections go through the same ErrorHandler as errors returned by handlers.
+package Middleware
+
+import (
+	"LiteFrame/Router/Error"
+	"LiteFrame/Router/Types"
+	"context"
+	"net/http"
+)
+
+// ErrorHandlerKey is an empty structure for identifying the router's error handler in context.
+// Used as a key for handler storage in context.WithValue.
+type ErrorHandlerKey struct{}
+
+// WithErrorHandler returns a copy of ctx carrying handler as the fallback used by HandleError.
+// Tree.ServeHTTP stores Tree.ErrorHandler this way when one is configured.
+func WithErrorHandler(ctx context.Context, handler Types.ErrorHandlerFunc) context.Context {
+	return context.WithValue(ctx, ErrorHandlerKey{}, handler)
+}
+
+// HandleError writes the response for err: with handler if not nil, otherwise with the error
+// handler stored in the request context (the tree's ErrorHandler), otherwise with Error.WriteError.
+// Middleware with an ErrorHandler field pass it as handler, so leaving the field nil makes
+// rejections look like every other error of the application.
+//
+// Usage example:
+//
+//	if !allowed {
+//	    Middleware.HandleError(writer, request, Error.NewErrorWithCode(Error.Forbidden, request.URL.Path), instance.ErrorHandler)
+//	    return
+//	}
+func HandleError(writer http.ResponseWriter, request *http.Request, err error, handler Types.ErrorHandlerFunc) {
+	if handler == nil {
+		handler, _ = request.Context().Value(ErrorHandlerKey{}).(Types.ErrorHandlerFunc)
+	}
+	if handler == nil {
+		handler = Error.WriteError
+	}
+	handler(writer, request, err)
+}
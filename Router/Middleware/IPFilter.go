@@ -0,0 +1,245 @@
+// Package Middleware provides IP allow and deny list filtering.
+// Matches client addresses against IPv4/IPv6 CIDR lists in a binary prefix trie,
+// with lists that can be replaced atomically while serving.
+package Middleware
+
+import (
+	"LiteFrame/Router/Error"
+	"LiteFrame/Router/Param"
+	"LiteFrame/Router/Types"
+	"bufio"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"os"
+	"strings"
+	"sync/atomic"
+)
+
+// PrefixSet is an immutable set of IP prefixes. Lookups walk a binary trie in at most
+// 32 (IPv4) or 128 (IPv6) steps regardless of the number of prefixes.
+type PrefixSet struct {
+	ipv4  *prefixNode // Trie of IPv4 prefixes
+	ipv6  *prefixNode // Trie of IPv6 prefixes
+	count int         // Number of distinct prefixes
+}
+
+// prefixNode is a binary trie node; terminal marks the end of a stored prefix.
+type prefixNode struct {
+	children [2]*prefixNode
+	terminal bool
+}
+
+// NewPrefixSet creates a PrefixSet from prefixes. IPv4-mapped IPv6 prefixes are stored as IPv4.
+func NewPrefixSet(prefixes []netip.Prefix) *PrefixSet {
+	set := &PrefixSet{ipv4: &prefixNode{}, ipv6: &prefixNode{}}
+	for _, prefix := range prefixes {
+		if !prefix.IsValid() {
+			continue
+		}
+		if prefix.Addr().Is4In6() && prefix.Bits() >= 96 {
+			prefix = netip.PrefixFrom(prefix.Addr().Unmap(), prefix.Bits()-96)
+		}
+		prefix = prefix.Masked()
+		node := set.root(prefix.Addr())
+		bytes := prefix.Addr().AsSlice()
+		for bit := 0; bit < prefix.Bits() && !node.terminal; bit++ {
+			branch := bytes[bit/8] >> (7 - bit%8) & 1
+			if node.children[branch] == nil {
+				node.children[branch] = &prefixNode{}
+			}
+			node = node.children[branch]
+		}
+		// Prefixes below node are covered by it now
+		node.terminal = true
+		node.children = [2]*prefixNode{}
+	}
+	set.count = set.ipv4.terminals() + set.ipv6.terminals()
+	return set
+}
+
+// terminals counts the stored prefixes below and including node.
+func (instance *prefixNode) terminals() int {
+	if instance == nil {
+		return 0
+	}
+	if instance.terminal {
+		return 1
+	}
+	return instance.children[0].terminals() + instance.children[1].terminals()
+}
+
+// root returns the trie for the family of addr.
+func (instance *PrefixSet) root(addr netip.Addr) *prefixNode {
+	if addr.Is4() {
+		return instance.ipv4
+	}
+	return instance.ipv6
+}
+
+// Contains reports whether addr is inside any prefix of the set.
+func (instance *PrefixSet) Contains(addr netip.Addr) bool {
+	if instance == nil || !addr.IsValid() {
+		return false
+	}
+	addr = addr.Unmap()
+	node := instance.root(addr)
+	bytes := addr.AsSlice()
+	for bit := 0; node != nil; bit++ {
+		if node.terminal {
+			return true
+		}
+		if bit == addr.BitLen() {
+			return false
+		}
+		node = node.children[bytes[bit/8]>>(7-bit%8)&1]
+	}
+	return false
+}
+
+// Len returns the number of prefixes in the set, excluding ones covered by broader prefixes.
+func (instance *PrefixSet) Len() int {
+	if instance == nil {
+		return 0
+	}
+	return instance.count
+}
+
+// LoadPrefixes reads prefixes from the file at path: one CIDR or address per line,
+// blank lines and "#" comments ignored.
+func LoadPrefixes(path string) ([]netip.Prefix, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var prefixes []netip.Prefix
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		value, _, _ := strings.Cut(scanner.Text(), "#")
+		if value = strings.TrimSpace(value); value == "" {
+			continue
+		}
+		parsed, err := ParsePrefixes([]string{value})
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		prefixes = append(prefixes, parsed...)
+	}
+	return prefixes, scanner.Err()
+}
+
+// ErrEmptyAllowList is returned by SetLists and Update when an IPFilter created with an allow
+// list would be left without one, which would admit every address.
+var ErrEmptyAllowList = errors.New("allow list is empty")
+
+// ipLists is one immutable generation of an IPFilter's lists.
+type ipLists struct {
+	allow *PrefixSet
+	deny  *PrefixSet
+}
+
+// IPFilter is a middleware that admits requests by client IP (see ClientIP).
+//
+// A request is rejected with 403 when its IP is in the deny list, or when the allow list is
+// not empty and does not contain it. Deny takes precedence, so a broad allow list can carry
+// narrow exceptions. Requests whose client IP cannot be parsed are rejected whenever an allow
+// list is set. An IPFilter without lists (the zero value) rejects every request.
+//
+// Lists are swapped atomically with SetLists or Update, so they can be reloaded while serving;
+// in-flight requests finish with the lists they started with. A filter created with an allow
+// list keeps requiring one: reloading an empty or truncated file fails with ErrEmptyAllowList
+// and leaves the current lists in place instead of opening the filter to everyone.
+// Register the filter on a Tree.Group to restrict a subtree, and behind RealIP when running
+// behind proxies.
+//
+// Usage example:
+//
+//	office, err := Middleware.NewIPFilter([]string{"198.51.100.0/24", "2001:db8:100::/48"}, nil)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	admin := tree.Group("/admin", office)
+//
+//	// Reload on SIGHUP
+//	allow, err := Middleware.LoadPrefixes("/etc/app/office.txt")
+//	if err == nil {
+//	    err = office.SetLists(allow, nil)
+//	}
+//	if err != nil {
+//	    log.Printf("keeping office list: %v", err)
+//	}
+type IPFilter struct {
+	ErrorHandler Types.ErrorHandlerFunc // Writes 403 responses (nil: the tree's, see HandleError)
+
+	lists        atomic.Pointer[ipLists] // Current lists
+	requireAllow bool                    // Created with an allow list; empty ones are rejected
+}
+
+// NewIPFilter creates an IPFilter from allow and deny lists of CIDRs or single addresses.
+// A filter with an empty allow list only applies deny; a filter with a non-empty one never
+// accepts an empty allow list later. Returns an error if any entry fails to parse.
+func NewIPFilter(allow []string, deny []string) (*IPFilter, error) {
+	filter := &IPFilter{requireAllow: len(allow) > 0}
+	if err := filter.Update(allow, deny); err != nil {
+		return nil, err
+	}
+	return filter, nil
+}
+
+// Update parses allow and deny and replaces the current lists.
+// The lists are left unchanged if any entry fails to parse or SetLists rejects them.
+func (instance *IPFilter) Update(allow []string, deny []string) error {
+	allowPrefixes, err := ParsePrefixes(allow)
+	if err != nil {
+		return fmt.Errorf("allow list: %w", err)
+	}
+	denyPrefixes, err := ParsePrefixes(deny)
+	if err != nil {
+		return fmt.Errorf("deny list: %w", err)
+	}
+	return instance.SetLists(allowPrefixes, denyPrefixes)
+}
+
+// SetLists replaces the current lists. An empty allow list admits every address not denied;
+// it is rejected with ErrEmptyAllowList if the filter was created with an allow list,
+// leaving the current lists unchanged.
+func (instance *IPFilter) SetLists(allow []netip.Prefix, deny []netip.Prefix) error {
+	allowSet := NewPrefixSet(allow)
+	if instance.requireAllow && allowSet.Len() == 0 {
+		return ErrEmptyAllowList
+	}
+	instance.lists.Store(&ipLists{allow: allowSet, deny: NewPrefixSet(deny)})
+	return nil
+}
+
+// Allowed reports whether the current lists admit addr.
+// An invalid addr is only admitted when the allow list is empty; nothing is admitted before
+// lists are set.
+func (instance *IPFilter) Allowed(addr netip.Addr) bool {
+	lists := instance.lists.Load()
+	if lists == nil {
+		return false
+	}
+	if lists.deny.Contains(addr) {
+		return false
+	}
+	return lists.allow.Len() == 0 || lists.allow.Contains(addr)
+}
+
+// GetHandler implements the Middleware interface.
+// The lists are read on every request, so later SetLists and Update calls take effect immediately.
+func (instance *IPFilter) GetHandler() MiddleWareFunc {
+	return func(next Types.HandlerFunc) Types.HandlerFunc {
+		return func(writer http.ResponseWriter, request *http.Request, params *Param.Params) {
+			addr, _ := netip.ParseAddr(ClientIP(request))
+			if !instance.Allowed(addr) {
+				HandleError(writer, request, Error.NewErrorWithCode(Error.Forbidden, request.URL.Path), instance.ErrorHandler)
+				return
+			}
+			next(writer, request, params)
+		}
+	}
+}
@@ -0,0 +1,367 @@
+// Package Middleware provides JSON Web Token verification.
+// Verifies HS256, RS256 and ES256 signatures with the standard library, checks the
+// exp, nbf, aud and iss claims and loads verification keys from a JWKS file.
+package Middleware
+
+import (
+	"LiteFrame/Router/Error"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// JWT signing algorithms supported by JWTVerifier.
+const (
+	AlgorithmHS256 = "HS256" // HMAC with SHA-256
+	AlgorithmRS256 = "RS256" // RSASSA-PKCS1-v1_5 with SHA-256
+	AlgorithmES256 = "ES256" // ECDSA with P-256 and SHA-256
+)
+
+// Token verification errors: Reasons wrapped in the Unauthorized error returned by JWTVerifier.
+var (
+	ErrTokenMalformed   = errors.New("malformed token")
+	ErrTokenAlgorithm   = errors.New("token algorithm not allowed")
+	ErrTokenKeyNotFound = errors.New("no key for token")
+	ErrTokenSignature   = errors.New("invalid token signature")
+	ErrTokenExpired     = errors.New("token expired")
+	ErrTokenNotYetValid = errors.New("token not yet valid")
+	ErrTokenAudience    = errors.New("token audience mismatch")
+	ErrTokenIssuer      = errors.New("token issuer mismatch")
+)
+
+// JWK is a verification key of a JWKS.
+type JWK struct {
+	ID        string // Key ID ("kid"), matched against the token header when present
+	Algorithm string // Algorithm the key is restricted to ("": any compatible algorithm)
+	Key       any    // []byte (HS256), *rsa.PublicKey (RS256) or *ecdsa.PublicKey (ES256)
+}
+
+// JWKS is a set of verification keys.
+type JWKS struct {
+	Keys []JWK
+}
+
+// jsonWebKey is the JSON representation of a key in a JWKS document (RFC 7517).
+type jsonWebKey struct {
+	Type      string `json:"kty"`
+	ID        string `json:"kid"`
+	Algorithm string `json:"alg"`
+	Use       string `json:"use"`
+	N         string `json:"n"`
+	E         string `json:"e"`
+	Curve     string `json:"crv"`
+	X         string `json:"x"`
+	Y         string `json:"y"`
+	K         string `json:"k"`
+}
+
+// LoadJWKS reads a JWKS document from the file at path.
+func LoadJWKS(path string) (*JWKS, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseJWKS(data)
+}
+
+// ParseJWKS parses a JWKS document containing RSA, P-256 EC and symmetric ("oct") keys.
+// Keys marked for encryption ("use": "enc") are skipped.
+func ParseJWKS(data []byte) (*JWKS, error) {
+	var document struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("jwks: %w", err)
+	}
+	keys := &JWKS{Keys: make([]JWK, 0, len(document.Keys))}
+	for index, raw := range document.Keys {
+		if raw.Use == "enc" {
+			continue
+		}
+		key, err := raw.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("jwks: key %d (%q): %w", index, raw.ID, err)
+		}
+		keys.Keys = append(keys.Keys, JWK{ID: raw.ID, Algorithm: raw.Algorithm, Key: key})
+	}
+	return keys, nil
+}
+
+// publicKey decodes the key material of raw.
+func (raw jsonWebKey) publicKey() (any, error) {
+	decode := base64.RawURLEncoding.DecodeString
+	switch raw.Type {
+	case "oct":
+		return decode(raw.K)
+	case "RSA":
+		n, err := decode(raw.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decode(raw.E)
+		if err != nil {
+			return nil, err
+		}
+		exponent := new(big.Int).SetBytes(e)
+		if len(n) == 0 || !exponent.IsInt64() || exponent.Int64() < 3 || exponent.Int64() > 1<<31-1 {
+			return nil, errors.New("invalid RSA key")
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, nil
+	case "EC":
+		if raw.Curve != "P-256" {
+			return nil, fmt.Errorf("unsupported curve %q", raw.Curve)
+		}
+		x, err := decode(raw.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decode(raw.Y)
+		if err != nil {
+			return nil, err
+		}
+		key := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !key.Curve.IsOnCurve(key.X, key.Y) {
+			return nil, errors.New("invalid EC key")
+		}
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", raw.Type)
+	}
+}
+
+// candidates returns the keys that may verify a token with the given kid and algorithm.
+func (instance *JWKS) candidates(kid string, algorithm string) []JWK {
+	var keys []JWK
+	for _, key := range instance.Keys {
+		if kid != "" && key.ID != kid {
+			continue
+		}
+		if key.Algorithm != "" && key.Algorithm != algorithm {
+			continue
+		}
+		if keyMatchesAlgorithm(key.Key, algorithm) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// keyMatchesAlgorithm reports whether key has the type required by algorithm.
+// This prevents algorithm confusion such as verifying HS256 with an RSA public key.
+func keyMatchesAlgorithm(key any, algorithm string) bool {
+	switch key := key.(type) {
+	case []byte:
+		return algorithm == AlgorithmHS256 && len(key) > 0
+	case *rsa.PublicKey:
+		return algorithm == AlgorithmRS256
+	case *ecdsa.PublicKey:
+		return algorithm == AlgorithmES256 && key.Curve == elliptic.P256()
+	}
+	return false
+}
+
+// JWTVerifier verifies JSON Web Tokens (RFC 7519) in compact serialization.
+//
+// The token header's "alg" must be one of Algorithms and match the key type, so a token
+// cannot choose a weaker algorithm than its key allows. When the header has a "kid", only
+// the key with that ID is tried; otherwise every compatible key is.
+//
+// Usage example:
+//
+//	keys, err := Middleware.LoadJWKS("/etc/app/jwks.json")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	verifier := Middleware.NewJWTVerifier(keys, "https://api.example.com")
+//	verifier.Issuer = "https://auth.example.com"
+//	api := tree.Group("/api", Middleware.NewJWTAuth("api", verifier))
+type JWTVerifier struct {
+	Keys       *JWKS            // Verification keys
+	Algorithms []string         // Accepted algorithms (nil: HS256, RS256 and ES256)
+	Audience   string           // Required "aud" value ("": not checked)
+	Issuer     string           // Required "iss" value ("": not checked)
+	Leeway     time.Duration    // Allowed clock skew for exp and nbf
+	Clock      func() time.Time // Time source (nil: time.Now)
+}
+
+// NewJWTVerifier creates a JWTVerifier for keys requiring audience, with one minute of leeway.
+func NewJWTVerifier(keys *JWKS, audience string) *JWTVerifier {
+	return &JWTVerifier{
+		Keys:     keys,
+		Audience: audience,
+		Leeway:   time.Minute,
+		Clock:    time.Now,
+	}
+}
+
+// NewJWTAuth creates a BearerAuth middleware that accepts tokens verified by verifier.
+func NewJWTAuth(realm string, verifier *JWTVerifier) *BearerAuth {
+	return NewBearerAuth(realm, verifier.Validate)
+}
+
+// Validate implements TokenValidator. The principal's Subject is the "sub" claim.
+func (instance *JWTVerifier) Validate(_ context.Context, token string) (*Principal, error) {
+	claims, err := instance.Verify(token)
+	if err != nil {
+		return nil, err
+	}
+	subject, _ := claims["sub"].(string)
+	return &Principal{Subject: subject, Scheme: "Bearer", Claims: claims}, nil
+}
+
+// Verify checks the signature and claims of token and returns its claims.
+// Errors are Unauthorized LiteFrameErrors wrapping one of the ErrToken* reasons.
+func (instance *JWTVerifier) Verify(token string) (map[string]any, error) {
+	claims, err := instance.verify(token)
+	if err != nil {
+		return nil, Error.WithCause(Error.Unauthorized, "", err)
+	}
+	return claims, nil
+}
+
+// verify implements Verify with bare reasons.
+func (instance *JWTVerifier) verify(token string) (map[string]any, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrTokenMalformed
+	}
+	var header struct {
+		Algorithm string `json:"alg"`
+		KeyID     string `json:"kid"`
+		Critical  []any  `json:"crit"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil || len(header.Critical) > 0 {
+		return nil, ErrTokenMalformed
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrTokenMalformed
+	}
+
+	algorithms := instance.Algorithms
+	if algorithms == nil {
+		algorithms = []string{AlgorithmHS256, AlgorithmRS256, AlgorithmES256}
+	}
+	if !slices.Contains(algorithms, header.Algorithm) {
+		return nil, ErrTokenAlgorithm
+	}
+	if instance.Keys == nil {
+		return nil, ErrTokenKeyNotFound
+	}
+	keys := instance.Keys.candidates(header.KeyID, header.Algorithm)
+	if len(keys) == 0 {
+		return nil, ErrTokenKeyNotFound
+	}
+	signed := []byte(parts[0] + "." + parts[1])
+	if !slices.ContainsFunc(keys, func(key JWK) bool { return verifySignature(header.Algorithm, key.Key, signed, signature) }) {
+		return nil, ErrTokenSignature
+	}
+
+	var claims map[string]any
+	if err := decodeSegment(parts[1], &claims); err != nil || claims == nil {
+		return nil, ErrTokenMalformed
+	}
+	if err := instance.checkClaims(claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// verifySignature reports whether signature is valid for signed under key.
+func verifySignature(algorithm string, key any, signed []byte, signature []byte) bool {
+	digest := sha256.Sum256(signed)
+	switch algorithm {
+	case AlgorithmHS256:
+		mac := hmac.New(sha256.New, key.([]byte))
+		mac.Write(signed)
+		return hmac.Equal(mac.Sum(nil), signature)
+	case AlgorithmRS256:
+		return rsa.VerifyPKCS1v15(key.(*rsa.PublicKey), crypto.SHA256, digest[:], signature) == nil
+	case AlgorithmES256:
+		// JWS encodes ES256 signatures as the 32-byte big-endian R and S concatenated
+		if len(signature) != 64 {
+			return false
+		}
+		r := new(big.Int).SetBytes(signature[:32])
+		s := new(big.Int).SetBytes(signature[32:])
+		return ecdsa.Verify(key.(*ecdsa.PublicKey), digest[:], r, s)
+	}
+	return false
+}
+
+// checkClaims validates the registered time, audience and issuer claims.
+func (instance *JWTVerifier) checkClaims(claims map[string]any) error {
+	now := time.Now()
+	if instance.Clock != nil {
+		now = instance.Clock()
+	}
+	if expires, ok, err := numericDate(claims, "exp"); err != nil {
+		return err
+	} else if ok && !now.Before(expires.Add(instance.Leeway)) {
+		return ErrTokenExpired
+	}
+	if notBefore, ok, err := numericDate(claims, "nbf"); err != nil {
+		return err
+	} else if ok && now.Add(instance.Leeway).Before(notBefore) {
+		return ErrTokenNotYetValid
+	}
+	if instance.Audience != "" && !hasAudience(claims["aud"], instance.Audience) {
+		return ErrTokenAudience
+	}
+	if instance.Issuer != "" {
+		if issuer, _ := claims["iss"].(string); issuer != instance.Issuer {
+			return ErrTokenIssuer
+		}
+	}
+	return nil
+}
+
+// numericDate reads the NumericDate claim name. Reports whether the claim is present.
+func numericDate(claims map[string]any, name string) (time.Time, bool, error) {
+	value, ok := claims[name]
+	if !ok {
+		return time.Time{}, false, nil
+	}
+	seconds, ok := value.(float64)
+	if !ok {
+		return time.Time{}, false, ErrTokenMalformed
+	}
+	return time.Unix(int64(seconds), 0), true, nil
+}
+
+// hasAudience reports whether the "aud" claim (a string or an array of strings) contains audience.
+func hasAudience(claim any, audience string) bool {
+	switch claim := claim.(type) {
+	case string:
+		return claim == audience
+	case []any:
+		for _, value := range claim {
+			if value == audience {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// decodeSegment decodes a base64url JSON token segment into target.
+func decodeSegment(segment string, target any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, target)
+}
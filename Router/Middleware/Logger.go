@@ -0,0 +1,276 @@
+// Package Middleware provides structured access logging middleware.
+// Logs method, route pattern, status, size, latency and client IP using log/slog,
+// or writes Common/Combined Log Format lines.
+package Middleware
+
+import (
+	"LiteFrame/Router/Param"
+	"LiteFrame/Router/Types"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// LogField is a bit set selecting the attributes of structured access log records.
+type LogField uint32
+
+// LogField constants: Attributes that can be included in structured access logs.
+const (
+	LogMethod    LogField = 1 << iota // HTTP method
+	LogPath                           // Raw URL path
+	LogRoute                          // Matched route pattern (/users/:id)
+	LogStatus                         // Response status code
+	LogBytes                          // Response body size in bytes
+	LogLatency                        // Time spent serving the request
+	LogClientIP                       // Client IP address
+	LogUserAgent                      // User-Agent header
+	LogReferer                        // Referer header
+	LogQuery                          // Raw query string
+	LogProto                          // Protocol version (HTTP/1.1, HTTP/2.0)
+	LogRequestID                      // Request ID assigned by the RequestID middleware
+
+	// DefaultLogFields is the field set used by NewLogger.
+	DefaultLogFields = LogMethod | LogPath | LogRoute | LogStatus | LogBytes | LogLatency | LogClientIP | LogRequestID
+)
+
+// LogFormat is an enumeration of access log output formats.
+type LogFormat uint32
+
+// LogFormat constants: Output formats supported by Logger.
+const (
+	LogStructured LogFormat = iota // log/slog record with the selected fields
+	LogCommon                      // NCSA Common Log Format line written to Output
+	LogCombined                    // Combined Log Format (Common + Referer and User-Agent)
+)
+
+// CommonLogTimeFormat is the timestamp layout used by Common and Combined Log Format.
+const CommonLogTimeFormat = "02/Jan/2006:15:04:05 -0700"
+
+// Logger is an access logging middleware.
+//
+// Sampling: Responses with status below 400 are logged with probability SampleRate;
+// client and server errors are always logged.
+//
+// Usage example:
+//
+//	logger := Middleware.NewLogger()
+//	logger.Fields |= Middleware.LogUserAgent
+//	logger.SampleRate = 0.1
+//	tree.SetMiddleware(logger)
+type Logger struct {
+	Logger     *slog.Logger               // Destination of structured records (nil: slog.Default())
+	Fields     LogField                   // Attributes included in structured records
+	Format     LogFormat                  // Output format
+	Output     io.Writer                  // Destination of Common/Combined lines (nil: os.Stdout)
+	SampleRate float64                    // Fraction of successful requests to log (1: all, 0: none)
+	ClientIP   func(*http.Request) string // Client IP resolver (default: ClientIP)
+	Clock      func() time.Time           // Time source (default: time.Now)
+	Sample     func() float64             // Random source in [0, 1) for sampling (default: rand.Float64)
+	mutex      sync.Mutex                 // Serializes writes to Output
+}
+
+// NewLogger creates a structured Logger with DefaultLogFields that logs every request.
+func NewLogger() *Logger {
+	return &Logger{
+		Fields:     DefaultLogFields,
+		Format:     LogStructured,
+		SampleRate: 1,
+		ClientIP:   ClientIP,
+		Clock:      time.Now,
+		Sample:     rand.Float64,
+	}
+}
+
+// RemoteIP returns the IP part of request.RemoteAddr.
+// Returns RemoteAddr unchanged if it has no port.
+func RemoteIP(request *http.Request) string {
+	host, _, err := net.SplitHostPort(request.RemoteAddr)
+	if err != nil {
+		return request.RemoteAddr
+	}
+	return host
+}
+
+// GetHandler implements the Middleware interface.
+func (instance *Logger) GetHandler() MiddleWareFunc {
+	return func(next Types.HandlerFunc) Types.HandlerFunc {
+		return func(writer http.ResponseWriter, request *http.Request, params *Param.Params) {
+			start := instance.now()
+			recorder, wrapped := WrapResponseWriter(writer)
+			var slot *principalSlot
+			if instance.Format == LogCommon || instance.Format == LogCombined {
+				// The authuser field needs the principal of an Auth middleware running further in
+				var ctx context.Context
+				ctx, slot = withPrincipalSlot(request.Context())
+				request = request.WithContext(ctx)
+			}
+			next(wrapped, request, params)
+			if !instance.ShouldLog(recorder.Status) {
+				return
+			}
+			latency := instance.now().Sub(start)
+			switch instance.Format {
+			case LogCommon, LogCombined:
+				if principal := slot.principal.Load(); principal != nil {
+					request = request.WithContext(WithPrincipal(request.Context(), principal))
+				}
+				instance.WriteLine(request, recorder, start)
+			default:
+				instance.LogRecord(request, recorder, latency)
+			}
+		}
+	}
+}
+
+// ShouldLog applies sampling to a response with the given status.
+func (instance *Logger) ShouldLog(status int) bool {
+	if status >= http.StatusBadRequest || instance.SampleRate >= 1 {
+		return true
+	}
+	if instance.SampleRate <= 0 {
+		return false
+	}
+	sample := rand.Float64
+	if instance.Sample != nil {
+		sample = instance.Sample
+	}
+	return sample() < instance.SampleRate
+}
+
+// LogRecord emits a structured access log record.
+// Level is Error for 5xx, Warn for 4xx and Info otherwise.
+func (instance *Logger) LogRecord(request *http.Request, recorder *ResponseWriter, latency time.Duration) {
+	logger := instance.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	level := slog.LevelInfo
+	switch {
+	case recorder.Status >= http.StatusInternalServerError:
+		level = slog.LevelError
+	case recorder.Status >= http.StatusBadRequest:
+		level = slog.LevelWarn
+	}
+	if !logger.Enabled(request.Context(), level) {
+		return
+	}
+	logger.LogAttrs(context.WithoutCancel(request.Context()), level, "request", instance.Attributes(request, recorder, latency)...)
+}
+
+// Attributes builds the slog attributes selected by Fields.
+func (instance *Logger) Attributes(request *http.Request, recorder *ResponseWriter, latency time.Duration) []slog.Attr {
+	attributes := make([]slog.Attr, 0, 12)
+	if instance.Fields&LogMethod != 0 {
+		attributes = append(attributes, slog.String("method", request.Method))
+	}
+	if instance.Fields&LogPath != 0 {
+		attributes = append(attributes, slog.String("path", request.URL.Path))
+	}
+	if instance.Fields&LogRoute != 0 {
+		attributes = append(attributes, slog.String("route", request.Pattern))
+	}
+	if instance.Fields&LogQuery != 0 {
+		attributes = append(attributes, slog.String("query", request.URL.RawQuery))
+	}
+	if instance.Fields&LogProto != 0 {
+		attributes = append(attributes, slog.String("proto", request.Proto))
+	}
+	if instance.Fields&LogStatus != 0 {
+		attributes = append(attributes, slog.Int("status", recorder.Status))
+	}
+	if instance.Fields&LogBytes != 0 {
+		attributes = append(attributes, slog.Int64("bytes", recorder.Bytes))
+	}
+	if instance.Fields&LogLatency != 0 {
+		attributes = append(attributes, slog.Duration("latency", latency))
+	}
+	if instance.Fields&LogClientIP != 0 {
+		attributes = append(attributes, slog.String("client_ip", instance.clientIP(request)))
+	}
+	if instance.Fields&LogRequestID != 0 {
+		if id := GetRequestID(request.Context()); id != "" {
+			attributes = append(attributes, slog.String("request_id", id))
+		}
+	}
+	if instance.Fields&LogUserAgent != 0 {
+		attributes = append(attributes, slog.String("user_agent", request.UserAgent()))
+	}
+	if instance.Fields&LogReferer != 0 {
+		attributes = append(attributes, slog.String("referer", request.Referer()))
+	}
+	return attributes
+}
+
+// WriteLine writes a Common or Combined Log Format line to Output.
+//
+// Common:   host ident authuser [date] "method uri proto" status bytes
+// Combined: Common + "referer" "user-agent"
+//
+// authuser is the Subject of the principal in the request context (see GetPrincipal), escaped
+// with escapeLogToken; unauthenticated requests get "-". Credentials the client merely sent
+// are never logged, as they are unverified and could forge fields or lines.
+func (instance *Logger) WriteLine(request *http.Request, recorder *ResponseWriter, start time.Time) {
+	user := "-"
+	if principal := GetPrincipal(request.Context()); principal != nil && principal.Subject != "" {
+		user = escapeLogToken(principal.Subject)
+	}
+	size := "-"
+	if recorder.Bytes > 0 {
+		size = strconv.FormatInt(recorder.Bytes, 10)
+	}
+	line := fmt.Sprintf("%s - %s [%s] \"%s %s %s\" %d %s",
+		instance.clientIP(request), user, start.Format(CommonLogTimeFormat),
+		request.Method, request.URL.RequestURI(), request.Proto, recorder.Status, size)
+	if instance.Format == LogCombined {
+		line += fmt.Sprintf(" %q %q", request.Referer(), request.UserAgent())
+	}
+	line += "\n"
+
+	output := instance.Output
+	if output == nil {
+		output = os.Stdout
+	}
+	instance.mutex.Lock()
+	_, _ = io.WriteString(output, line)
+	instance.mutex.Unlock()
+}
+
+// escapeLogToken makes value a single log field: spaces, quotes, backslashes, control and
+// non-ASCII bytes are written as \xHH, like Apache does for its log fields.
+func escapeLogToken(value string) string {
+	const hex = "0123456789ABCDEF"
+	escaped := make([]byte, 0, len(value))
+	for index := 0; index < len(value); index++ {
+		char := value[index]
+		if char <= ' ' || char >= 0x7f || char == '"' || char == '\\' {
+			escaped = append(escaped, '\\', 'x', hex[char>>4], hex[char&0xf])
+			continue
+		}
+		escaped = append(escaped, char)
+	}
+	return string(escaped)
+}
+
+// now returns the current time from Clock.
+func (instance *Logger) now() time.Time {
+	if instance.Clock != nil {
+		return instance.Clock()
+	}
+	return time.Now()
+}
+
+// clientIP resolves the client IP with ClientIP.
+func (instance *Logger) clientIP(request *http.Request) string {
+	if instance.ClientIP != nil {
+		return instance.ClientIP(request)
+	}
+	return ClientIP(request)
+}
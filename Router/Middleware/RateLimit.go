@@ -0,0 +1,174 @@
+// Package Middleware provides rate limiting middleware.
+// Throttles requests per key (client IP, API key, route parameter, route) using a pluggable Store.
+package Middleware
+
+import (
+	"LiteFrame/Router/Error"
+	"LiteFrame/Router/Param"
+	"LiteFrame/Router/Types"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// KeyFunc extracts the rate limit key of a request.
+// An empty key exempts the request from limiting.
+type KeyFunc func(request *http.Request, params *Param.Params) string
+
+// KeyByIP keys requests by client IP (see ClientIP).
+func KeyByIP(request *http.Request, _ *Param.Params) string {
+	return ClientIP(request)
+}
+
+// KeyByRoute keys requests by matched route pattern, limiting each route as a whole.
+func KeyByRoute(request *http.Request, _ *Param.Params) string {
+	return request.Pattern
+}
+
+// KeyByHeader returns a KeyFunc keying requests by the value of header (e.g. an API key).
+func KeyByHeader(header string) KeyFunc {
+	return func(request *http.Request, _ *Param.Params) string {
+		return request.Header.Get(header)
+	}
+}
+
+// KeyByParam returns a KeyFunc keying requests by the route parameter name.
+func KeyByParam(name string) KeyFunc {
+	return func(_ *http.Request, params *Param.Params) string {
+		if params == nil {
+			return ""
+		}
+		return params.GetByName(name)
+	}
+}
+
+// CombineKeys returns a KeyFunc joining the keys of all functions with "|".
+// The request is exempt if any of them returns an empty key.
+func CombineKeys(functions ...KeyFunc) KeyFunc {
+	return func(request *http.Request, params *Param.Params) string {
+		parts := make([]string, len(functions))
+		for index, function := range functions {
+			if parts[index] = function(request, params); parts[index] == "" {
+				return ""
+			}
+		}
+		return strings.Join(parts, "|")
+	}
+}
+
+// RateLimit is a rate limiting middleware.
+//
+// Every response carries RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset (seconds),
+// plus RateLimit-Policy describing the quota. Rejected requests also get Retry-After and
+// a 429 response written by ErrorHandler.
+//
+// Routes overrides the policy per route pattern; overridden routes are counted separately
+// from the default policy. Register separate RateLimit instances on Tree groups for
+// group-wide policies.
+//
+// Usage example:
+//
+//	limiter := Middleware.NewRateLimit(Middleware.Limit{Requests: 100, Period: time.Minute})
+//	limiter.Key = Middleware.KeyByHeader("X-API-Key")
+//	limiter.Routes = map[string]Middleware.Limit{"/login": {Requests: 5, Period: time.Minute}}
+//	tree.SetMiddleware(limiter)
+type RateLimit struct {
+	Limit        Limit                  // Default policy
+	Routes       map[string]Limit       // Per-pattern policy overrides
+	Key          KeyFunc                // Key extractor (nil: KeyByIP)
+	Store        Store                  // Rate limit state (nil: in-memory store created by GetHandler)
+	FailOpen     bool                   // Whether to allow requests when the Store fails (otherwise 500)
+	ErrorHandler Types.ErrorHandlerFunc // Writes 429 and store failure responses (nil: the tree's, see HandleError)
+	Clock        func() time.Time       // Time source (nil: time.Now)
+
+	once  sync.Once // Guards creation of the default store
+	store Store     // Store in use
+}
+
+// NewRateLimit creates a RateLimit middleware keyed by client IP with an in-memory store.
+func NewRateLimit(limit Limit) *RateLimit {
+	return &RateLimit{
+		Limit: limit,
+		Key:   KeyByIP,
+		Store: NewMemoryStore(DefaultStoreShards),
+		Clock: time.Now,
+	}
+}
+
+// GetHandler implements the Middleware interface.
+// The Store is resolved on the first call and shared by every route using this instance.
+func (instance *RateLimit) GetHandler() MiddleWareFunc {
+	instance.once.Do(func() {
+		instance.store = instance.Store
+		if instance.store == nil {
+			instance.store = NewMemoryStore(DefaultStoreShards)
+		}
+	})
+	store := instance.store
+	keyFunc := instance.Key
+	if keyFunc == nil {
+		keyFunc = KeyByIP
+	}
+	return func(next Types.HandlerFunc) Types.HandlerFunc {
+		return func(writer http.ResponseWriter, request *http.Request, params *Param.Params) {
+			key := keyFunc(request, params)
+			if key == "" {
+				next(writer, request, params)
+				return
+			}
+			limit := instance.Limit
+			if override, ok := instance.Routes[request.Pattern]; ok {
+				limit = override
+				key = request.Pattern + " " + key
+			}
+			if limit.Requests <= 0 || limit.Period <= 0 {
+				next(writer, request, params)
+				return
+			}
+
+			decision, err := store.Allow(request.Context(), key, limit, instance.now())
+			if err != nil {
+				if instance.FailOpen {
+					next(writer, request, params)
+					return
+				}
+				HandleError(writer, request, fmt.Errorf("rate limit store: %w", err), instance.ErrorHandler)
+				return
+			}
+			SetRateLimitHeaders(writer.Header(), limit, decision)
+			if !decision.Allowed {
+				HandleError(writer, request, Error.NewErrorWithCode(Error.TooManyRequests, request.URL.Path), instance.ErrorHandler)
+				return
+			}
+			next(writer, request, params)
+		}
+	}
+}
+
+// SetRateLimitHeaders writes the RateLimit-* headers and, for rejected requests, Retry-After.
+// Durations are rounded up to whole seconds.
+func SetRateLimitHeaders(header http.Header, limit Limit, decision Decision) {
+	header.Set("RateLimit-Limit", strconv.Itoa(decision.Limit))
+	header.Set("RateLimit-Remaining", strconv.Itoa(decision.Remaining))
+	header.Set("RateLimit-Reset", strconv.FormatInt(ceilSeconds(decision.Reset), 10))
+	header.Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", limit.Requests, ceilSeconds(limit.Period)))
+	if !decision.Allowed {
+		header.Set("Retry-After", strconv.FormatInt(max(ceilSeconds(decision.RetryAfter), 1), 10))
+	}
+}
+
+// ceilSeconds converts duration to whole seconds, rounding up.
+func ceilSeconds(duration time.Duration) int64 {
+	return int64((duration + time.Second - 1) / time.Second)
+}
+
+// now returns the current time from Clock.
+func (instance *RateLimit) now() time.Time {
+	if instance.Clock != nil {
+		return instance.Clock()
+	}
+	return time.Now()
+}
@@ -0,0 +1,197 @@
+// Package Middleware provides rate limit algorithms and storage.
+// Implements token bucket and sliding window limiting in a sharded in-memory store.
+package Middleware
+
+import (
+	"context"
+	"hash/maphash"
+	"math"
+	"sync"
+	"time"
+)
+
+// RateAlgorithm is an enumeration of rate limiting algorithms.
+type RateAlgorithm uint32
+
+// RateAlgorithm constants: Algorithms supported by MemoryStore.
+const (
+	TokenBucket   RateAlgorithm = iota // Refills Limit tokens per Period up to Burst; allows short bursts
+	SlidingWindow                      // Weighted count over the current and previous Period
+)
+
+// Limit describes a rate limit policy: Requests per Period with the given algorithm.
+type Limit struct {
+	Requests  int           // Requests allowed per Period
+	Period    time.Duration // Length of the window or refill period
+	Burst     int           // Token bucket capacity (0: Requests)
+	Algorithm RateAlgorithm // Limiting algorithm
+}
+
+// Decision is the outcome of a rate limit check.
+type Decision struct {
+	Allowed    bool          // Whether the request may proceed
+	Limit      int           // Requests allowed per period (quota shown to clients)
+	Remaining  int           // Requests left in the current period
+	Reset      time.Duration // Time until the quota is fully available again
+	RetryAfter time.Duration // Time until the next request would be allowed (0 if allowed)
+}
+
+// Store keeps rate limit state per key.
+// Implementations must apply the check and the update atomically; external backends
+// (e.g. Redis) typically do this in a server-side script. now may be ignored by
+// backends that use their own clock.
+type Store interface {
+	Allow(ctx context.Context, key string, limit Limit, now time.Time) (Decision, error)
+}
+
+// DefaultStoreShards is the number of shards used by NewMemoryStore.
+const DefaultStoreShards = 64
+
+// MemoryStore is an in-memory Store sharded by key hash to reduce lock contention.
+// Entries are evicted once they carry no state: TokenBucket entries when the bucket would be
+// full again, SlidingWindow entries after two idle Periods. Eviction is lazy: each shard is
+// swept at most once per SweepInterval during normal access.
+type MemoryStore struct {
+	shards        []memoryShard
+	seed          maphash.Seed
+	SweepInterval time.Duration // Minimum time between sweeps of a shard
+}
+
+// memoryShard is one lock-protected partition of a MemoryStore.
+type memoryShard struct {
+	mutex     sync.Mutex
+	entries   map[string]*rateEntry
+	nextSweep time.Time
+}
+
+// rateEntry is the state of one key.
+type rateEntry struct {
+	tokens   float64   // TokenBucket: available tokens
+	last     time.Time // TokenBucket: last refill time
+	window   time.Time // SlidingWindow: start of the current window
+	current  int       // SlidingWindow: requests in the current window
+	previous int       // SlidingWindow: requests in the previous window
+	expires  time.Time // Eviction time
+}
+
+// NewMemoryStore creates a MemoryStore with the given number of shards (DefaultStoreShards if <= 0).
+func NewMemoryStore(shards int) *MemoryStore {
+	if shards <= 0 {
+		shards = DefaultStoreShards
+	}
+	store := &MemoryStore{
+		shards:        make([]memoryShard, shards),
+		seed:          maphash.MakeSeed(),
+		SweepInterval: time.Minute,
+	}
+	for index := range store.shards {
+		store.shards[index].entries = make(map[string]*rateEntry)
+	}
+	return store
+}
+
+// Allow implements Store.
+func (instance *MemoryStore) Allow(_ context.Context, key string, limit Limit, now time.Time) (Decision, error) {
+	shard := &instance.shards[maphash.String(instance.seed, key)%uint64(len(instance.shards))]
+	shard.mutex.Lock()
+	defer shard.mutex.Unlock()
+
+	if now.After(shard.nextSweep) {
+		for entryKey, entry := range shard.entries {
+			if now.After(entry.expires) {
+				delete(shard.entries, entryKey)
+			}
+		}
+		shard.nextSweep = now.Add(instance.SweepInterval)
+	}
+
+	entry, ok := shard.entries[key]
+	if !ok || now.After(entry.expires) {
+		entry = &rateEntry{tokens: float64(limit.capacity()), last: now, window: now}
+		shard.entries[key] = entry
+	}
+	if limit.Algorithm == SlidingWindow {
+		entry.expires = now.Add(2 * limit.Period)
+		return entry.slidingWindow(limit, now), nil
+	}
+	// A bucket left alone for capacity*perToken is full again, the same as a new one
+	entry.expires = now.Add(time.Duration(limit.capacity()) * limit.perToken())
+	return entry.tokenBucket(limit, now), nil
+}
+
+// Len returns the number of tracked keys, including expired ones not yet swept.
+func (instance *MemoryStore) Len() int {
+	total := 0
+	for index := range instance.shards {
+		instance.shards[index].mutex.Lock()
+		total += len(instance.shards[index].entries)
+		instance.shards[index].mutex.Unlock()
+	}
+	return total
+}
+
+// capacity returns the token bucket capacity.
+func (instance Limit) capacity() int {
+	if instance.Burst > 0 {
+		return instance.Burst
+	}
+	return instance.Requests
+}
+
+// perToken returns the token bucket refill interval of one token.
+func (instance Limit) perToken() time.Duration {
+	return instance.Period / time.Duration(max(instance.Requests, 1))
+}
+
+// tokenBucket refills the bucket and takes one token if available.
+func (instance *rateEntry) tokenBucket(limit Limit, now time.Time) Decision {
+	capacity := float64(limit.capacity())
+	perToken := limit.perToken()
+	if elapsed := now.Sub(instance.last); elapsed > 0 {
+		instance.tokens = math.Min(capacity, instance.tokens+float64(elapsed)/float64(perToken))
+		instance.last = now
+	}
+	decision := Decision{Limit: limit.capacity()}
+	if instance.tokens >= 1 {
+		instance.tokens--
+		decision.Allowed = true
+	} else {
+		decision.RetryAfter = time.Duration((1 - instance.tokens) * float64(perToken))
+	}
+	decision.Remaining = int(instance.tokens)
+	decision.Reset = time.Duration((capacity - instance.tokens) * float64(perToken))
+	return decision
+}
+
+// slidingWindow counts the request against a weighted sum of the current and previous window.
+func (instance *rateEntry) slidingWindow(limit Limit, now time.Time) Decision {
+	elapsed := now.Sub(instance.window)
+	if elapsed >= limit.Period {
+		windows := elapsed / limit.Period
+		if windows == 1 {
+			instance.previous = instance.current
+		} else {
+			instance.previous = 0
+		}
+		instance.current = 0
+		instance.window = instance.window.Add(windows * limit.Period)
+		elapsed -= windows * limit.Period
+	}
+	weight := 1 - float64(elapsed)/float64(limit.Period)
+	estimated := float64(instance.previous)*weight + float64(instance.current)
+
+	decision := Decision{Limit: limit.Requests, Reset: limit.Period - elapsed}
+	if estimated+1 <= float64(limit.Requests) {
+		instance.current++
+		estimated++
+		decision.Allowed = true
+	} else if instance.previous > 0 {
+		// Time until the previous window's weight drops enough to admit one more request
+		needed := (estimated + 1 - float64(limit.Requests)) / float64(instance.previous)
+		decision.RetryAfter = min(time.Duration(needed*float64(limit.Period)), limit.Period-elapsed)
+	} else {
+		decision.RetryAfter = limit.Period - elapsed
+	}
+	decision.Remaining = max(int(float64(limit.Requests)-estimated), 0)
+	return decision
+}
@@ -0,0 +1,293 @@
+// Package Middleware provides client address resolution behind reverse proxies.
+// Derives the real client IP and scheme from the forwarding header a configured proxy sets
+// (Forwarded, X-Forwarded-For or X-Real-IP), trusting it only when the request came from that proxy.
+package Middleware
+
+import (
+	"LiteFrame/Router/Param"
+	"LiteFrame/Router/Types"
+	"context"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// Forwarding headers understood by RealIP.
+const (
+	HeaderForwarded       = "Forwarded"         // RFC 7239
+	HeaderXForwardedFor   = "X-Forwarded-For"   // De facto client chain
+	HeaderXForwardedProto = "X-Forwarded-Proto" // De facto scheme, paired with X-Forwarded-For
+	HeaderXRealIP         = "X-Real-IP"         // Single client address (nginx)
+)
+
+// RealIPKey is an empty structure for identifying the resolved client address in context.
+// Used as a key for address storage in context.WithValue.
+type RealIPKey struct{}
+
+// realAddress is the value stored under RealIPKey.
+type realAddress struct {
+	ip     string
+	scheme string
+}
+
+// RealIP is a middleware that resolves the client IP and scheme of proxied requests.
+//
+// Forwarding headers are only read when the direct peer (RemoteAddr) is in TrustedProxies.
+// The address chain is then walked from the nearest hop outwards, skipping trusted proxies;
+// the first untrusted address is the client. Unparseable or obfuscated hops stop the walk at
+// the last trusted proxy, so clients cannot inject addresses through spoofed headers.
+// The first header in Headers that is present wins; without Headers, RemoteAddr is used as is.
+//
+// Only list headers that every trusted proxy overwrites, or appends the peer address to in the
+// case of Forwarded and X-Forwarded-For. A header the proxy passes through unchanged is set by
+// the client: if the proxy only maintains X-Forwarded-For, listing Forwarded lets any client
+// choose its address. There is deliberately no default; name the header your proxy sets.
+//
+// The scheme comes from the Forwarded "proto" of the chosen hop, or from X-Forwarded-Proto
+// (the entry paired with the chosen X-Forwarded-For address when the counts match, otherwise
+// the last one). Without headers it is "https" for TLS connections and "http" otherwise.
+//
+// The result is read with ClientIP and RequestScheme, which rate limiting, logging, CSRF,
+// security headers and IP filtering use. Register RealIP before those middlewares (and
+// before Logger, which reads the client IP of the request it was given).
+//
+// Usage example:
+//
+//	realIP, err := Middleware.NewRealIP(Middleware.HeaderXForwardedFor, "10.0.0.0/8", "fd00::/8")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	tree.SetMiddleware(realIP)
+//	tree.SetMiddleware(Middleware.NewLogger())
+type RealIP struct {
+	TrustedProxies []netip.Prefix // Networks whose forwarding headers are trusted
+	Headers        []string       // Headers set by the trusted proxies, consulted in order (nil: none)
+}
+
+// NewRealIP creates a RealIP middleware trusting header on requests from the given CIDRs or single addresses.
+// header is the forwarding header the proxies set (HeaderForwarded, HeaderXForwardedFor or
+// HeaderXRealIP); an empty header disables header resolution.
+// Returns an error if any of the proxies fails to parse.
+func NewRealIP(header string, trustedProxies ...string) (*RealIP, error) {
+	prefixes, err := ParsePrefixes(trustedProxies)
+	if err != nil {
+		return nil, err
+	}
+	realIP := &RealIP{TrustedProxies: prefixes}
+	if header != "" {
+		realIP.Headers = []string{header}
+	}
+	return realIP, nil
+}
+
+// ParsePrefixes parses CIDRs ("10.0.0.0/8", "2001:db8::/32") and single addresses, which become
+// /32 or /128 prefixes. IPv4-mapped IPv6 addresses are unmapped.
+func ParsePrefixes(values []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if !strings.Contains(value, "/") {
+			addr, err := netip.ParseAddr(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid address %q: %w", value, err)
+			}
+			addr = addr.Unmap()
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR %q: %w", value, err)
+		}
+		if prefix.Addr().Is4In6() && prefix.Bits() >= 96 {
+			prefix = netip.PrefixFrom(prefix.Addr().Unmap(), prefix.Bits()-96)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+// GetHandler implements the Middleware interface.
+func (instance *RealIP) GetHandler() MiddleWareFunc {
+	return func(next Types.HandlerFunc) Types.HandlerFunc {
+		return func(writer http.ResponseWriter, request *http.Request, params *Param.Params) {
+			ip, scheme := instance.Resolve(request)
+			next(writer, request.WithContext(WithRealIP(request.Context(), ip, scheme)), params)
+		}
+	}
+}
+
+// WithRealIP returns a copy of ctx carrying the resolved client ip and scheme.
+func WithRealIP(ctx context.Context, ip string, scheme string) context.Context {
+	return context.WithValue(ctx, RealIPKey{}, realAddress{ip: ip, scheme: scheme})
+}
+
+// ClientIP returns the client IP used by IP-based middleware: the address resolved by RealIP,
+// or the IP part of RemoteAddr when RealIP did not run.
+func ClientIP(request *http.Request) string {
+	if address, ok := request.Context().Value(RealIPKey{}).(realAddress); ok {
+		return address.ip
+	}
+	return RemoteIP(request)
+}
+
+// RequestScheme returns the scheme ("http" or "https") the client used: the scheme resolved
+// by RealIP, or "https" for requests received over TLS and "http" otherwise.
+func RequestScheme(request *http.Request) string {
+	if address, ok := request.Context().Value(RealIPKey{}).(realAddress); ok {
+		return address.scheme
+	}
+	return connectionScheme(request)
+}
+
+// connectionScheme returns the scheme of the direct connection.
+func connectionScheme(request *http.Request) string {
+	if request.TLS != nil {
+		return "https"
+	}
+	return "http"
+}
+
+// Resolve returns the client IP and scheme of request.
+func (instance *RealIP) Resolve(request *http.Request) (string, string) {
+	remote := RemoteIP(request)
+	scheme := connectionScheme(request)
+	peer, err := netip.ParseAddr(remote)
+	if err != nil || !instance.trusted(peer) {
+		return remote, scheme
+	}
+
+	for _, name := range instance.Headers {
+		values := request.Header.Values(name)
+		if len(values) == 0 {
+			continue
+		}
+		switch http.CanonicalHeaderKey(name) {
+		case HeaderForwarded:
+			hops, protos := parseForwarded(values)
+			return instance.walk(peer, scheme, hops, protos)
+		case HeaderXForwardedFor:
+			hops := splitHeaderList(values)
+			protos := pairProtos(splitHeaderList(request.Header.Values(HeaderXForwardedProto)), len(hops))
+			return instance.walk(peer, scheme, hops, protos)
+		default:
+			return instance.walk(peer, scheme, []string{strings.TrimSpace(values[0])}, nil)
+		}
+	}
+	return remote, scheme
+}
+
+// walk returns the address and scheme of the first untrusted hop, scanning from the right.
+// protos is nil or parallel to hops; empty entries keep the current scheme.
+func (instance *RealIP) walk(peer netip.Addr, scheme string, hops []string, protos []string) (string, string) {
+	client := peer
+	for index := len(hops) - 1; index >= 0; index-- {
+		addr, ok := parseNode(hops[index])
+		if !ok {
+			break
+		}
+		client = addr
+		if protos != nil {
+			if proto := strings.ToLower(protos[index]); proto == "http" || proto == "https" {
+				scheme = proto
+			}
+		}
+		if !instance.trusted(addr) {
+			break
+		}
+	}
+	return client.String(), scheme
+}
+
+// pairProtos aligns X-Forwarded-Proto entries with count X-Forwarded-For hops.
+// Without a one-to-one match every hop gets the last entry.
+func pairProtos(protos []string, count int) []string {
+	if len(protos) == 0 || len(protos) == count {
+		return protos
+	}
+	paired := make([]string, count)
+	for index := range paired {
+		paired[index] = protos[len(protos)-1]
+	}
+	return paired
+}
+
+// trusted reports whether addr is in TrustedProxies.
+func (instance *RealIP) trusted(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	for _, prefix := range instance.TrustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseForwarded returns the "for" and "proto" parameters of every Forwarded element in order.
+func parseForwarded(values []string) ([]string, []string) {
+	var hops, protos []string
+	for _, value := range values {
+		for _, element := range splitQuoted(value, ',') {
+			node, proto := "", ""
+			for _, pair := range splitQuoted(element, ';') {
+				key, value, _ := strings.Cut(strings.TrimSpace(pair), "=")
+				value = strings.Trim(strings.TrimSpace(value), `"`)
+				switch strings.ToLower(key) {
+				case "for":
+					node = value
+				case "proto":
+					proto = value
+				}
+			}
+			hops = append(hops, node)
+			protos = append(protos, proto)
+		}
+	}
+	return hops, protos
+}
+
+// splitQuoted splits value at separator outside of double quotes.
+func splitQuoted(value string, separator byte) []string {
+	var parts []string
+	quoted, start := false, 0
+	for index := 0; index < len(value); index++ {
+		switch value[index] {
+		case '"':
+			quoted = !quoted
+		case '\\':
+			index++
+		case separator:
+			if !quoted {
+				parts = append(parts, value[start:index])
+				start = index + 1
+			}
+		}
+	}
+	return append(parts, value[start:])
+}
+
+// splitHeaderList splits comma separated header values into trimmed entries.
+func splitHeaderList(values []string) []string {
+	var entries []string
+	for _, value := range values {
+		for _, entry := range strings.Split(value, ",") {
+			entries = append(entries, strings.TrimSpace(entry))
+		}
+	}
+	return entries
+}
+
+// parseNode parses a hop address: "192.0.2.1", "192.0.2.1:80", "[2001:db8::1]:80" or "2001:db8::1".
+// Obfuscated identifiers and "unknown" are rejected.
+func parseNode(node string) (netip.Addr, bool) {
+	if addrPort, err := netip.ParseAddrPort(node); err == nil {
+		return addrPort.Addr().Unmap(), true
+	}
+	node = strings.TrimSuffix(strings.TrimPrefix(node, "["), "]")
+	addr, err := netip.ParseAddr(node)
+	if err != nil || addr.Zone() != "" {
+		return netip.Addr{}, false
+	}
+	return addr.Unmap(), true
+}
@@ -0,0 +1,110 @@
+// Package Middleware provides panic recovery middleware.
+// Converts handler panics into 500 responses and reports them with stack traces.
+package Middleware
+
+import (
+	"LiteFrame/Router/Param"
+	"LiteFrame/Router/Types"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+)
+
+// PanicInfo describes a recovered panic.
+type PanicInfo struct {
+	Value     any           // Value passed to panic
+	Stack     []byte        // Stack trace of the panicking goroutine
+	Pattern   string        // Matched route pattern (empty if no route matched)
+	RequestID string        // Request ID from the RequestID middleware (empty if none)
+	Request   *http.Request // Request being served when the panic occurred
+}
+
+// PanicError is the error passed to the ErrorHandler after a recovered panic.
+// Unwrap returns the panic value when it is an error.
+type PanicError struct {
+	Value any // Value passed to panic
+}
+
+// Error implements the error interface.
+func (instance *PanicError) Error() string {
+	return fmt.Sprintf("panic recovered: %v", instance.Value)
+}
+
+// Unwrap returns the panic value if it is an error.
+func (instance *PanicError) Unwrap() error {
+	if err, ok := instance.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// Recovery is a middleware that recovers from panics in downstream handlers.
+// http.ErrAbortHandler is re-panicked so net/http can abort the connection as intended.
+// The PanicError goes through HandleError like every other middleware error, so with a nil
+// ErrorHandler the tree's ErrorHandler writes the response. No response is written if the
+// handler already sent the header or hijacked the connection, or if SkipResponse is set.
+//
+// Usage example:
+//
+//	recovery := Middleware.NewRecovery()
+//	recovery.Hook = func(info Middleware.PanicInfo) { reportToTracker(info) }
+//	tree.SetMiddleware(recovery)
+type Recovery struct {
+	Hook         func(PanicInfo)        // Called with panic details before responding (nil: no hook)
+	ErrorHandler Types.ErrorHandlerFunc // Writes the response for the PanicError (nil: the tree's, see HandleError)
+	SkipResponse bool                   // Whether to leave the response unwritten after a panic
+}
+
+// NewRecovery creates a Recovery middleware with default settings.
+// The default hook logs the panic with log/slog; the response comes from the tree's
+// ErrorHandler, or Error.WriteError's 500 problem+json without the panic value.
+func NewRecovery() *Recovery {
+	return &Recovery{
+		Hook: LogPanic,
+	}
+}
+
+// LogPanic logs panic details at error level using the default slog logger.
+func LogPanic(info PanicInfo) {
+	slog.Error("panic recovered",
+		slog.Any("panic", info.Value),
+		slog.String("method", info.Request.Method),
+		slog.String("path", info.Request.URL.Path),
+		slog.String("route", info.Pattern),
+		slog.String("request_id", info.RequestID),
+		slog.String("stack", string(info.Stack)),
+	)
+}
+
+// GetHandler implements the Middleware interface.
+func (instance *Recovery) GetHandler() MiddleWareFunc {
+	return func(next Types.HandlerFunc) Types.HandlerFunc {
+		return func(writer http.ResponseWriter, request *http.Request, params *Param.Params) {
+			recorder, wrapped := WrapResponseWriter(writer)
+			defer func() {
+				recovered := recover()
+				if recovered == nil {
+					return
+				}
+				if err, ok := recovered.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+					panic(recovered)
+				}
+				if instance.Hook != nil {
+					instance.Hook(PanicInfo{
+						Value:     recovered,
+						Stack:     debug.Stack(),
+						Pattern:   request.Pattern,
+						RequestID: GetRequestID(request.Context()),
+						Request:   request,
+					})
+				}
+				if !instance.SkipResponse && !recorder.WroteHeader && !recorder.Hijacked {
+					HandleError(wrapped, request, &PanicError{Value: recovered}, instance.ErrorHandler)
+				}
+			}()
+			next(wrapped, request, params)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+// Package Middleware provides request ID propagation middleware.
+// Assigns every request a correlation ID that is stored in the context and echoed in the response.
+package Middleware
+
+import (
+	"LiteFrame/Router/Param"
+	"LiteFrame/Router/Types"
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultRequestIDHeader is the header read and written by NewRequestID.
+const DefaultRequestIDHeader = "X-Request-ID"
+
+// MaxRequestIDLength is the maximum length of an accepted incoming request ID.
+const MaxRequestIDLength = 128
+
+// RequestIDKey is an empty structure for identifying the request ID in context.
+// Used as a key for request ID storage in context.WithValue.
+type RequestIDKey struct{}
+
+// RequestID is a middleware that assigns a correlation ID to each request.
+// An incoming ID is reused when it is valid; otherwise Generator creates a new one.
+// The ID is stored in the request context and echoed in the response header.
+//
+// Register it before Logger and Recovery so their output carries the ID.
+//
+// Usage example:
+//
+//	tree.SetMiddleware(Middleware.NewRequestID())
+//	tree.SetMiddleware(Middleware.NewLogger())
+//	...
+//	id := Middleware.GetRequestID(request.Context())
+type RequestID struct {
+	Header     string            // Header carrying the ID (empty: DefaultRequestIDHeader)
+	Generator  func() string     // ID source (nil: NewRequestIDString)
+	TrustInput bool              // Whether to reuse an incoming ID
+	Validator  func(string) bool // Incoming ID check (nil: ValidRequestID)
+}
+
+// NewRequestID creates a RequestID middleware that reuses valid incoming X-Request-ID values
+// and generates sortable IDs otherwise.
+func NewRequestID() *RequestID {
+	return &RequestID{
+		Header:     DefaultRequestIDHeader,
+		Generator:  NewRequestIDString,
+		TrustInput: true,
+		Validator:  ValidRequestID,
+	}
+}
+
+// GetHandler implements the Middleware interface.
+func (instance *RequestID) GetHandler() MiddleWareFunc {
+	header := instance.Header
+	if header == "" {
+		header = DefaultRequestIDHeader
+	}
+	return func(next Types.HandlerFunc) Types.HandlerFunc {
+		return func(writer http.ResponseWriter, request *http.Request, params *Param.Params) {
+			id := ""
+			if instance.TrustInput {
+				id = request.Header.Get(header)
+				if !instance.valid(id) {
+					id = ""
+				}
+			}
+			if id == "" {
+				id = instance.generate()
+			}
+			writer.Header().Set(header, id)
+			next(writer, request.WithContext(WithRequestID(request.Context(), id)), params)
+		}
+	}
+}
+
+// WithRequestID returns a copy of ctx carrying id.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, RequestIDKey{}, id)
+}
+
+// GetRequestID extracts the request ID from context.
+// Returns an empty string if none was stored.
+func GetRequestID(ctx context.Context) string {
+	id, _ := ctx.Value(RequestIDKey{}).(string)
+	return id
+}
+
+// ValidRequestID reports whether id is safe to propagate: non-empty, at most
+// MaxRequestIDLength bytes and made of printable ASCII without spaces, so that it
+// cannot inject content into log lines or response headers.
+func ValidRequestID(id string) bool {
+	if id == "" || len(id) > MaxRequestIDLength {
+		return false
+	}
+	for index := 0; index < len(id); index++ {
+		if id[index] <= ' ' || id[index] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// crockford is the Crockford base32 alphabet; it sorts in the same order as the values it encodes.
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// requestIDState holds the last generated ID for monotonic generation.
+var requestIDState struct {
+	mutex  sync.Mutex
+	last   int64    // Millisecond timestamp of the last ID
+	random [10]byte // Random part of the last ID
+}
+
+// NewRequestIDString generates a 26 character ULID-style ID: a 48-bit millisecond timestamp
+// followed by 80 random bits, encoded in Crockford base32.
+// IDs sort lexicographically by creation time; IDs created within the same millisecond
+// increment the random part so they stay strictly ordered within the process.
+func NewRequestIDString() string {
+	var data [16]byte
+	now := time.Now().UnixMilli()
+
+	requestIDState.mutex.Lock()
+	if now <= requestIDState.last {
+		now = requestIDState.last
+		incrementRandom(&requestIDState.random)
+	} else {
+		_, _ = rand.Read(requestIDState.random[:])
+		requestIDState.last = now
+	}
+	copy(data[6:], requestIDState.random[:])
+	requestIDState.mutex.Unlock()
+
+	var timestamp [8]byte
+	binary.BigEndian.PutUint64(timestamp[:], uint64(now))
+	copy(data[:6], timestamp[2:])
+	return encodeCrockford(data)
+}
+
+// incrementRandom adds one to the big-endian random part.
+func incrementRandom(random *[10]byte) {
+	for index := len(random) - 1; index >= 0; index-- {
+		random[index]++
+		if random[index] != 0 {
+			return
+		}
+	}
+}
+
+// encodeCrockford encodes 128 bits as 26 base32 characters (the first carries 3 bits).
+func encodeCrockford(data [16]byte) string {
+	high := binary.BigEndian.Uint64(data[:8])
+	low := binary.BigEndian.Uint64(data[8:])
+	var output [26]byte
+	for index := 25; index >= 0; index-- {
+		output[index] = crockford[low&0x1F]
+		low = low>>5 | high<<59
+		high >>= 5
+	}
+	return string(output[:])
+}
+
+// generate creates a new ID with Generator.
+func (instance *RequestID) generate() string {
+	if instance.Generator != nil {
+		return instance.Generator()
+	}
+	return NewRequestIDString()
+}
+
+// valid checks an incoming ID with Validator.
+func (instance *RequestID) valid(id string) bool {
+	if instance.Validator != nil {
+		return instance.Validator(id)
+	}
+	return ValidRequestID(id)
+}
@@ -0,0 +1,262 @@
+// Package Middleware provides a response writer wrapper that records response metadata.
+// Shared by middleware that needs the status code or size of the response (logging, metrics, recovery).
+package Middleware
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+)
+
+// NewResponseWriter wraps writer to record status code and bytes written.
+// Status defaults to 200, which net/http sends when a handler writes without calling WriteHeader.
+//
+// The returned value implements only http.ResponseWriter; use WrapResponseWriter to keep
+// the optional interfaces of writer available to downstream handlers.
+func NewResponseWriter(writer http.ResponseWriter) *ResponseWriter {
+	return &ResponseWriter{
+		ResponseWriter: writer,
+		Status:         http.StatusOK,
+	}
+}
+
+// ResponseWriter is an http.ResponseWriter wrapper that records response metadata.
+type ResponseWriter struct {
+	http.ResponseWriter       // Underlying writer
+	Status              int   // Status code sent (200 if WriteHeader was never called)
+	Bytes               int64 // Number of body bytes written
+	WroteHeader         bool  // Whether the header has been sent
+	Hijacked            bool  // Whether the connection was hijacked
+}
+
+// Recorder is implemented by every writer returned from WrapResponseWriter.
+// Lets nested middleware find and share the recording wrapper.
+type Recorder interface {
+	Recorder() *ResponseWriter
+}
+
+// Recorder returns the wrapper itself.
+func (instance *ResponseWriter) Recorder() *ResponseWriter {
+	return instance
+}
+
+// WriteHeader records the status code and forwards it.
+// Only the first call is recorded, matching net/http semantics; informational (1xx)
+// responses are forwarded without marking the header as written.
+func (instance *ResponseWriter) WriteHeader(status int) {
+	if !instance.WroteHeader {
+		if status >= 100 && status < 200 && status != http.StatusSwitchingProtocols {
+			instance.ResponseWriter.WriteHeader(status)
+			return
+		}
+		instance.Status = status
+		instance.WroteHeader = true
+	}
+	instance.ResponseWriter.WriteHeader(status)
+}
+
+// Write records the number of bytes written and forwards the data.
+func (instance *ResponseWriter) Write(data []byte) (int, error) {
+	instance.WroteHeader = true
+	written, err := instance.ResponseWriter.Write(data)
+	instance.Bytes += int64(written)
+	return written, err
+}
+
+// Unwrap returns the underlying writer for http.ResponseController.
+func (instance *ResponseWriter) Unwrap() http.ResponseWriter {
+	return instance.ResponseWriter
+}
+
+// flush sends buffered data; an implicit 200 header is sent if none was written.
+func (instance *ResponseWriter) flush() {
+	instance.WroteHeader = true
+	instance.ResponseWriter.(http.Flusher).Flush()
+}
+
+// hijack takes over the connection.
+func (instance *ResponseWriter) hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, buffer, err := instance.ResponseWriter.(http.Hijacker).Hijack()
+	if err == nil {
+		instance.Hijacked = true
+	}
+	return conn, buffer, err
+}
+
+// readFrom copies source to the underlying writer, keeping its sendfile/splice optimizations.
+func (instance *ResponseWriter) readFrom(source io.Reader) (int64, error) {
+	instance.WroteHeader = true
+	written, err := instance.ResponseWriter.(io.ReaderFrom).ReadFrom(source)
+	instance.Bytes += written
+	return written, err
+}
+
+// push initiates an HTTP/2 server push.
+func (instance *ResponseWriter) push(target string, options *http.PushOptions) error {
+	return instance.ResponseWriter.(http.Pusher).Push(target, options)
+}
+
+// Function adapters used to attach optional interfaces to the wrapper.
+type flushFunc func()
+type hijackFunc func() (net.Conn, *bufio.ReadWriter, error)
+type readFromFunc func(io.Reader) (int64, error)
+type pushFunc func(string, *http.PushOptions) error
+
+func (function flushFunc) Flush()                                        { function() }
+func (function hijackFunc) Hijack() (net.Conn, *bufio.ReadWriter, error) { return function() }
+func (function readFromFunc) ReadFrom(source io.Reader) (int64, error)   { return function(source) }
+func (function pushFunc) Push(target string, options *http.PushOptions) error {
+	return function(target, options)
+}
+
+// Optional interface bits used to select the wrapper shape.
+const (
+	supportsFlusher = 1 << iota
+	supportsHijacker
+	supportsReaderFrom
+	supportsPusher
+)
+
+// WrapResponseWriter wraps writer in a recording ResponseWriter and returns it together with
+// a writer to pass downstream. The downstream writer implements exactly those of http.Flusher,
+// http.Hijacker, io.ReaderFrom and http.Pusher that writer implements, plus Unwrap for
+// http.ResponseController and Recorder.
+//
+// If writer was already returned by WrapResponseWriter, its recorder is reused and writer is
+// returned unchanged, so nested middleware share one set of counters.
+//
+// Usage example:
+//
+//	recorder, wrapped := Middleware.WrapResponseWriter(writer)
+//	next(wrapped, request, params)
+//	log.Println(recorder.Status, recorder.Bytes)
+func WrapResponseWriter(writer http.ResponseWriter) (*ResponseWriter, http.ResponseWriter) {
+	if existing, ok := writer.(Recorder); ok {
+		return existing.Recorder(), writer
+	}
+	return wrapResponseWriter(writer, writer)
+}
+
+// wrapResponseWriter wraps writer in a recording ResponseWriter whose downstream writer
+// implements the optional interfaces that shape implements. writer must implement at least
+// those; it is used by middleware whose own writer sits in front of shape (see Compress).
+func wrapResponseWriter(writer http.ResponseWriter, shape http.ResponseWriter) (*ResponseWriter, http.ResponseWriter) {
+	base := NewResponseWriter(writer)
+
+	mask := 0
+	if _, ok := shape.(http.Flusher); ok {
+		mask |= supportsFlusher
+	}
+	if _, ok := shape.(http.Hijacker); ok {
+		mask |= supportsHijacker
+	}
+	if _, ok := shape.(io.ReaderFrom); ok {
+		mask |= supportsReaderFrom
+	}
+	if _, ok := shape.(http.Pusher); ok {
+		mask |= supportsPusher
+	}
+
+	flusher := flushFunc(base.flush)
+	hijacker := hijackFunc(base.hijack)
+	readerFrom := readFromFunc(base.readFrom)
+	pusher := pushFunc(base.push)
+
+	switch mask {
+	case supportsFlusher:
+		return base, struct {
+			*ResponseWriter
+			http.Flusher
+		}{base, flusher}
+	case supportsHijacker:
+		return base, struct {
+			*ResponseWriter
+			http.Hijacker
+		}{base, hijacker}
+	case supportsReaderFrom:
+		return base, struct {
+			*ResponseWriter
+			io.ReaderFrom
+		}{base, readerFrom}
+	case supportsPusher:
+		return base, struct {
+			*ResponseWriter
+			http.Pusher
+		}{base, pusher}
+	case supportsFlusher | supportsHijacker:
+		return base, struct {
+			*ResponseWriter
+			http.Flusher
+			http.Hijacker
+		}{base, flusher, hijacker}
+	case supportsFlusher | supportsReaderFrom:
+		return base, struct {
+			*ResponseWriter
+			http.Flusher
+			io.ReaderFrom
+		}{base, flusher, readerFrom}
+	case supportsFlusher | supportsPusher:
+		return base, struct {
+			*ResponseWriter
+			http.Flusher
+			http.Pusher
+		}{base, flusher, pusher}
+	case supportsHijacker | supportsReaderFrom:
+		return base, struct {
+			*ResponseWriter
+			http.Hijacker
+			io.ReaderFrom
+		}{base, hijacker, readerFrom}
+	case supportsHijacker | supportsPusher:
+		return base, struct {
+			*ResponseWriter
+			http.Hijacker
+			http.Pusher
+		}{base, hijacker, pusher}
+	case supportsReaderFrom | supportsPusher:
+		return base, struct {
+			*ResponseWriter
+			io.ReaderFrom
+			http.Pusher
+		}{base, readerFrom, pusher}
+	case supportsFlusher | supportsHijacker | supportsReaderFrom:
+		return base, struct {
+			*ResponseWriter
+			http.Flusher
+			http.Hijacker
+			io.ReaderFrom
+		}{base, flusher, hijacker, readerFrom}
+	case supportsFlusher | supportsHijacker | supportsPusher:
+		return base, struct {
+			*ResponseWriter
+			http.Flusher
+			http.Hijacker
+			http.Pusher
+		}{base, flusher, hijacker, pusher}
+	case supportsFlusher | supportsReaderFrom | supportsPusher:
+		return base, struct {
+			*ResponseWriter
+			http.Flusher
+			io.ReaderFrom
+			http.Pusher
+		}{base, flusher, readerFrom, pusher}
+	case supportsHijacker | supportsReaderFrom | supportsPusher:
+		return base, struct {
+			*ResponseWriter
+			http.Hijacker
+			io.ReaderFrom
+			http.Pusher
+		}{base, hijacker, readerFrom, pusher}
+	case supportsFlusher | supportsHijacker | supportsReaderFrom | supportsPusher:
+		return base, struct {
+			*ResponseWriter
+			http.Flusher
+			http.Hijacker
+			io.ReaderFrom
+			http.Pusher
+		}{base, flusher, hijacker, readerFrom, pusher}
+	default:
+		return base, base
+	}
+}
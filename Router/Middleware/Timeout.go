@@ -0,0 +1,171 @@
+// Package Middleware provides per-request timeout middleware.
+// Bounds handler run time with a context deadline and answers 503 when it is exceeded.
+package Middleware
+
+import (
+	"LiteFrame/Router/Error"
+	"LiteFrame/Router/Param"
+	"LiteFrame/Router/Types"
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Timeout is a middleware that limits how long a handler may run.
+//
+// The handler runs in its own goroutine with a request context carrying the deadline; it should
+// watch request.Context().Done() and return early. Its response is buffered and only sent if it
+// finishes in time. Otherwise ErrorHandler writes the timeout response (503 by default), and
+// later writes from the handler fail with http.ErrHandlerTimeout, so the two never race.
+// Because of the buffering, streaming (Flush) is not available to handlers behind Timeout.
+//
+// Routes overrides the duration per route pattern (request.Pattern); a non-positive value
+// disables the timeout for that route. For a whole set of routes, register a separate
+// Timeout on a Tree.Group instead.
+//
+// Usage example:
+//
+//	timeout := Middleware.NewTimeout(5 * time.Second)
+//	timeout.Routes = map[string]time.Duration{"/reports/:id": time.Minute}
+//	tree.SetMiddleware(timeout)
+type Timeout struct {
+	Duration     time.Duration            // Default handler deadline (non-positive: no timeout)
+	Routes       map[string]time.Duration // Per-pattern overrides
+	ErrorHandler Types.ErrorHandlerFunc   // Writes the timeout response (nil: the tree's, see HandleError)
+}
+
+// NewTimeout creates a Timeout middleware with the given default duration.
+func NewTimeout(duration time.Duration) *Timeout {
+	return &Timeout{
+		Duration: duration,
+	}
+}
+
+// DurationFor returns the deadline applied to requests matching pattern.
+func (instance *Timeout) DurationFor(pattern string) time.Duration {
+	if duration, ok := instance.Routes[pattern]; ok {
+		return duration
+	}
+	return instance.Duration
+}
+
+// GetHandler implements the Middleware interface.
+func (instance *Timeout) GetHandler() MiddleWareFunc {
+	return func(next Types.HandlerFunc) Types.HandlerFunc {
+		return func(writer http.ResponseWriter, request *http.Request, params *Param.Params) {
+			duration := instance.DurationFor(request.Pattern)
+			if duration <= 0 {
+				next(writer, request, params)
+				return
+			}
+			ctx, cancel := context.WithTimeout(request.Context(), duration)
+			defer cancel()
+			request = request.WithContext(ctx)
+
+			buffered := &timeoutWriter{header: make(http.Header), status: http.StatusOK}
+			done := make(chan struct{})
+			panicked := make(chan any, 1)
+			// The handler may outlive this call, so it gets its own copy of the pooled params
+			handlerParams := params.Clone()
+			go func() {
+				defer func() {
+					if recovered := recover(); recovered != nil {
+						panicked <- recovered
+						return
+					}
+					close(done)
+				}()
+				next(buffered, request, handlerParams)
+				buffered.mutex.Lock()
+				buffered.finished = ctx.Err() == nil
+				buffered.mutex.Unlock()
+			}()
+
+			select {
+			case recovered := <-panicked:
+				// Re-panic on the serving goroutine so an outer Recovery can handle it
+				panic(recovered)
+			case <-done:
+			case <-ctx.Done():
+			}
+			// done and ctx.Done can both be ready when the handler returns right at the deadline,
+			// and select picks either; a handler that returned in time always wins
+			buffered.mutex.Lock()
+			finished := buffered.finished
+			buffered.timedOut = !finished
+			buffered.mutex.Unlock()
+			if finished {
+				buffered.flushTo(writer)
+				return
+			}
+			select {
+			case recovered := <-panicked:
+				panic(recovered)
+			default:
+			}
+			if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				// The client went away; nobody is left to read a response
+				return
+			}
+			HandleError(writer, request, Error.NewErrorWithCode(Error.RequestTimeout, request.URL.Path), instance.ErrorHandler)
+		}
+	}
+}
+
+// timeoutWriter buffers a handler response until it is known to have finished in time.
+type timeoutWriter struct {
+	mutex       sync.Mutex
+	header      http.Header
+	body        bytes.Buffer
+	status      int
+	wroteHeader bool
+	finished    bool
+	timedOut    bool
+}
+
+// Header returns the buffered header map.
+func (instance *timeoutWriter) Header() http.Header {
+	return instance.header
+}
+
+// WriteHeader records the status code of the buffered response.
+// Informational (1xx) statuses are dropped: they cannot be sent ahead of a buffered response.
+func (instance *timeoutWriter) WriteHeader(status int) {
+	instance.mutex.Lock()
+	defer instance.mutex.Unlock()
+	if instance.timedOut || instance.wroteHeader || status < http.StatusOK {
+		return
+	}
+	instance.status = status
+	instance.wroteHeader = true
+}
+
+// Write buffers data, or fails with http.ErrHandlerTimeout after the deadline.
+func (instance *timeoutWriter) Write(data []byte) (int, error) {
+	instance.mutex.Lock()
+	defer instance.mutex.Unlock()
+	if instance.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	instance.wroteHeader = true
+	return instance.body.Write(data)
+}
+
+// flushTo copies the buffered response to writer.
+func (instance *timeoutWriter) flushTo(writer http.ResponseWriter) {
+	instance.mutex.Lock()
+	defer instance.mutex.Unlock()
+	header := writer.Header()
+	for key, values := range instance.header {
+		header[key] = values
+	}
+	if !instance.wroteHeader {
+		// The handler wrote nothing; keep net/http's implicit response
+		return
+	}
+	writer.WriteHeader(instance.status)
+	_, _ = writer.Write(instance.body.Bytes())
+}
@@ -0,0 +1,151 @@
+package Middleware
+
+import (
+	"LiteFrame/Router/Error"
+	"LiteFrame/Router/Param"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// ======================
+// Auth Test Helpers
+// ======================
+
+// serveAuth runs request through middleware and returns the response and the principal seen by the handler.
+func serveAuth(middleware Middleware, request *http.Request) (*httptest.ResponseRecorder, *Principal) {
+	var principal *Principal
+	recorder := httptest.NewRecorder()
+	middleware.GetHandler()(func(w http.ResponseWriter, r *http.Request, params *Param.Params) {
+		principal = GetPrincipal(r.Context())
+	})(recorder, request, nil)
+	return recorder, principal
+}
+
+// ======================
+// Basic Auth Tests
+// ======================
+
+func TestBasicAuth(t *testing.T) {
+	auth := NewBasicAuth(`admin "area"`, BasicUsers(map[string]string{"alice": "s3cret"}))
+
+	tests := []struct {
+		name     string
+		username string
+		password string
+		setAuth  bool
+		expected int
+	}{
+		{"valid", "alice", "s3cret", true, http.StatusOK},
+		{"wrong_password", "alice", "s3cret!", true, http.StatusUnauthorized},
+		{"unknown_user", "bob", "s3cret", true, http.StatusUnauthorized},
+		{"missing", "", "", false, http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest("GET", "/admin", nil)
+			if tt.setAuth {
+				request.SetBasicAuth(tt.username, tt.password)
+			}
+			recorder, principal := serveAuth(auth, request)
+			if recorder.Code != tt.expected {
+				t.Fatalf("Expected %d, got %d", tt.expected, recorder.Code)
+			}
+			if tt.expected == http.StatusOK {
+				if principal == nil || principal.Subject != "alice" || principal.Scheme != "Basic" {
+					t.Errorf("Unexpected principal %+v", principal)
+				}
+				return
+			}
+			if challenge := recorder.Header().Get("WWW-Authenticate"); challenge != `Basic realm="admin \"area\"", charset="UTF-8"` {
+				t.Errorf("Unexpected challenge %q", challenge)
+			}
+		})
+	}
+}
+
+func TestBasicAuthValidatorErrors(t *testing.T) {
+	request := func() *http.Request {
+		request := httptest.NewRequest("GET", "/", nil)
+		request.SetBasicAuth("alice", "pw")
+		return request
+	}
+
+	t.Run("internal_failure", func(t *testing.T) {
+		auth := NewBasicAuth("app", func(context.Context, string, string) (*Principal, error) {
+			return nil, errors.New("user store down")
+		})
+		recorder, _ := serveAuth(auth, request())
+		if recorder.Code != http.StatusInternalServerError || recorder.Header().Get("WWW-Authenticate") != "" {
+			t.Errorf("Expected 500 without challenge, got %d %v", recorder.Code, recorder.Header())
+		}
+	})
+
+	t.Run("liteframe_error", func(t *testing.T) {
+		auth := NewBasicAuth("app", func(context.Context, string, string) (*Principal, error) {
+			return nil, Error.NewErrorWithCode(Error.TooManyRequests, "")
+		})
+		recorder, _ := serveAuth(auth, request())
+		if recorder.Code != http.StatusTooManyRequests {
+			t.Errorf("Expected validator error status, got %d", recorder.Code)
+		}
+	})
+
+	t.Run("nil_validator", func(t *testing.T) {
+		recorder, _ := serveAuth(&BasicAuth{Realm: "app"}, request())
+		if recorder.Code != http.StatusUnauthorized {
+			t.Errorf("Expected 401, got %d", recorder.Code)
+		}
+	})
+}
+
+// ======================
+// Bearer Auth Tests
+// ======================
+
+func TestBearerToken(t *testing.T) {
+	tests := map[string]string{
+		"Bearer abc":   "abc",
+		"bearer  abc ": "abc",
+		"Basic abc":    "",
+		"Bearer":       "",
+		"":             "",
+	}
+	for header, expected := range tests {
+		request := httptest.NewRequest("GET", "/", nil)
+		request.Header.Set("Authorization", header)
+		if token := BearerToken(request); token != expected {
+			t.Errorf("%q: expected %q, got %q", header, expected, token)
+		}
+	}
+}
+
+func TestBearerAuth(t *testing.T) {
+	auth := NewBearerAuth("api", func(_ context.Context, token string) (*Principal, error) {
+		if token != "good" {
+			return nil, nil
+		}
+		return &Principal{Subject: "client-1", Scheme: "Bearer"}, nil
+	})
+
+	request := httptest.NewRequest("GET", "/", nil)
+	request.Header.Set("Authorization", "Bearer good")
+	recorder, principal := serveAuth(auth, request)
+	if recorder.Code != http.StatusOK || principal == nil || principal.Subject != "client-1" {
+		t.Fatalf("Expected authenticated request, got %d %+v", recorder.Code, principal)
+	}
+
+	recorder, _ = serveAuth(auth, httptest.NewRequest("GET", "/", nil))
+	if recorder.Code != http.StatusUnauthorized || recorder.Header().Get("WWW-Authenticate") != `Bearer realm="api"` {
+		t.Errorf("Expected bare challenge without token, got %d %q", recorder.Code, recorder.Header().Get("WWW-Authenticate"))
+	}
+
+	request = httptest.NewRequest("GET", "/", nil)
+	request.Header.Set("Authorization", "Bearer bad")
+	recorder, _ = serveAuth(auth, request)
+	if recorder.Code != http.StatusUnauthorized || recorder.Header().Get("WWW-Authenticate") != `Bearer realm="api", error="invalid_token"` {
+		t.Errorf("Expected invalid_token challenge, got %d %q", recorder.Code, recorder.Header().Get("WWW-Authenticate"))
+	}
+}
@@ -0,0 +1,101 @@
+package Middleware
+
+import (
+	"LiteFrame/Router/Error"
+	"LiteFrame/Router/Param"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// ======================
+// Body Limit Tests
+// ======================
+
+func TestBodyLimit(t *testing.T) {
+	// readAll mimics an error-returning handler whose error reaches the central responder
+	readAll := func(w http.ResponseWriter, r *http.Request, params *Param.Params) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			Error.WriteError(w, r, err)
+			return
+		}
+		_, _ = w.Write(body)
+	}
+	serve := func(limit *BodyLimit, request *http.Request) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		limit.GetHandler()(readAll)(recorder, request, nil)
+		return recorder
+	}
+	post := func(body string, contentType string) *http.Request {
+		request := httptest.NewRequest("POST", "/upload", strings.NewReader(body))
+		if contentType != "" {
+			request.Header.Set("Content-Type", contentType)
+		}
+		return request
+	}
+
+	t.Run("within_limit", func(t *testing.T) {
+		recorder := serve(NewBodyLimit(10), post("hello", "text/plain"))
+		if recorder.Code != http.StatusOK || recorder.Body.String() != "hello" {
+			t.Errorf("Expected body to pass, got %d %q", recorder.Code, recorder.Body.String())
+		}
+	})
+
+	t.Run("declared_length_too_large", func(t *testing.T) {
+		called := false
+		recorder := httptest.NewRecorder()
+		NewBodyLimit(4).GetHandler()(func(w http.ResponseWriter, r *http.Request, params *Param.Params) {
+			called = true
+		})(recorder, post("hello", "text/plain"), nil)
+		if called || recorder.Code != http.StatusRequestEntityTooLarge {
+			t.Errorf("Expected 413 before the handler, got %d (called=%v)", recorder.Code, called)
+		}
+	})
+
+	t.Run("streamed_body_too_large", func(t *testing.T) {
+		request := post(strings.Repeat("x", 100), "text/plain")
+		request.ContentLength = -1 // Chunked: size unknown up front
+		recorder := serve(NewBodyLimit(10), request)
+		if recorder.Code != http.StatusRequestEntityTooLarge {
+			t.Errorf("Expected 413 from MaxBytesError, got %d", recorder.Code)
+		}
+		if recorder.Header().Get("Content-Type") != Error.ProblemContentType {
+			t.Errorf("Expected problem+json, got %q", recorder.Header().Get("Content-Type"))
+		}
+	})
+
+	t.Run("route_override", func(t *testing.T) {
+		limit := NewBodyLimit(4)
+		limit.Routes = map[string]int64{"/upload": 100, "/stream": -1}
+		for _, pattern := range []string{"/upload", "/stream"} {
+			request := post("hello world", "text/plain")
+			request.Pattern = pattern
+			if recorder := serve(limit, request); recorder.Code != http.StatusOK {
+				t.Errorf("%s: expected override to allow the body, got %d", pattern, recorder.Code)
+			}
+		}
+	})
+
+	t.Run("content_type", func(t *testing.T) {
+		limit := NewBodyLimit(100)
+		limit.ContentTypes = []string{"application/json", "text/"}
+		tests := map[string]int{
+			"application/json":                http.StatusOK,
+			"Application/JSON; charset=utf-8": http.StatusOK,
+			"text/csv":                        http.StatusOK,
+			"application/xml":                 http.StatusUnsupportedMediaType,
+			"":                                http.StatusUnsupportedMediaType,
+		}
+		for contentType, expected := range tests {
+			if recorder := serve(limit, post("{}", contentType)); recorder.Code != expected {
+				t.Errorf("%q: expected %d, got %d", contentType, expected, recorder.Code)
+			}
+		}
+		if recorder := serve(limit, httptest.NewRequest("GET", "/", nil)); recorder.Code != http.StatusOK {
+			t.Errorf("Expected bodiless request to skip the content type check, got %d", recorder.Code)
+		}
+	})
+}
@@ -0,0 +1,343 @@
+package Middleware
+
+import (
+	"LiteFrame/Router/Param"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// ======================
+// Compress Middleware Tests
+// ======================
+
+func serveCompressed(compress *Compress, request *http.Request, handler func(w http.ResponseWriter, r *http.Request)) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	compress.GetHandler()(func(w http.ResponseWriter, r *http.Request, params *Param.Params) {
+		handler(w, r)
+	})(recorder, request, nil)
+	return recorder
+}
+
+func compressRequest(method string, acceptEncoding string) *http.Request {
+	request := httptest.NewRequest(method, "/", nil)
+	if acceptEncoding != "" {
+		request.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	return request
+}
+
+func writeBody(contentType string, body string) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if contentType != "" {
+			w.Header().Set("Content-Type", contentType)
+		}
+		_, _ = io.WriteString(w, body)
+	}
+}
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := []struct {
+		header   string
+		expected string
+	}{
+		{"", EncodingIdentity},
+		{"gzip", EncodingGzip},
+		{"deflate", EncodingDeflate},
+		{"gzip, deflate, br", EncodingGzip},
+		{"deflate, gzip", EncodingGzip},
+		{"gzip;q=0.5, deflate;q=0.8", EncodingDeflate},
+		{"gzip;q=0, deflate", EncodingDeflate},
+		{"gzip;q=0, deflate;q=0", EncodingIdentity},
+		{"*", EncodingGzip},
+		{"*;q=0.1, gzip;q=0", EncodingDeflate},
+		{"br, identity", EncodingIdentity},
+		{"GZIP;Q=1.0", EncodingGzip},
+	}
+	for _, test := range tests {
+		if got := NegotiateEncoding(test.header); got != test.expected {
+			t.Errorf("NegotiateEncoding(%q): expected %s, got %s", test.header, test.expected, got)
+		}
+	}
+}
+
+func TestCompress(t *testing.T) {
+	large := strings.Repeat(`{"name":"LiteFrame","fast":true}`, 100)
+
+	t.Run("gzip", func(t *testing.T) {
+		recorder := serveCompressed(NewCompress(), compressRequest("GET", "gzip"), writeBody("application/json", large))
+		if recorder.Header().Get("Content-Encoding") != EncodingGzip {
+			t.Fatalf("Expected gzip encoding, got %v", recorder.Header())
+		}
+		if recorder.Header().Get("Vary") != "Accept-Encoding" {
+			t.Errorf("Expected Vary: Accept-Encoding, got %q", recorder.Header().Get("Vary"))
+		}
+		reader, err := gzip.NewReader(recorder.Body)
+		if err != nil {
+			t.Fatalf("Invalid gzip stream: %v", err)
+		}
+		body, _ := io.ReadAll(reader)
+		if string(body) != large {
+			t.Error("Decompressed body does not match")
+		}
+	})
+
+	t.Run("deflate", func(t *testing.T) {
+		recorder := serveCompressed(NewCompress(), compressRequest("GET", "deflate"), writeBody("text/html; charset=utf-8", large))
+		if recorder.Header().Get("Content-Encoding") != EncodingDeflate {
+			t.Fatalf("Expected deflate encoding, got %v", recorder.Header())
+		}
+		body, _ := io.ReadAll(flate.NewReader(recorder.Body))
+		if string(body) != large {
+			t.Error("Decompressed body does not match")
+		}
+	})
+
+	t.Run("chunked_writes_and_pool_reuse", func(t *testing.T) {
+		compress := NewCompress()
+		for round := 0; round < 3; round++ {
+			recorder := serveCompressed(compress, compressRequest("GET", "gzip"), func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/plain")
+				for index := 0; index < 100; index++ {
+					_, _ = io.WriteString(w, "chunk of text ")
+				}
+			})
+			reader, err := gzip.NewReader(recorder.Body)
+			if err != nil {
+				t.Fatalf("Round %d: invalid gzip stream: %v", round, err)
+			}
+			body, _ := io.ReadAll(reader)
+			if string(body) != strings.Repeat("chunk of text ", 100) {
+				t.Errorf("Round %d: decompressed body does not match", round)
+			}
+		}
+	})
+
+	t.Run("below_min_size", func(t *testing.T) {
+		recorder := serveCompressed(NewCompress(), compressRequest("GET", "gzip"), writeBody("application/json", `{"ok":true}`))
+		if recorder.Header().Get("Content-Encoding") != "" || recorder.Body.String() != `{"ok":true}` {
+			t.Errorf("Expected small body to stay uncompressed, got %v %q", recorder.Header(), recorder.Body.String())
+		}
+		if recorder.Header().Get("Vary") != "Accept-Encoding" {
+			t.Error("Expected Vary on an eligible but small response")
+		}
+	})
+
+	t.Run("content_type_not_allowed", func(t *testing.T) {
+		recorder := serveCompressed(NewCompress(), compressRequest("GET", "gzip"), writeBody("image/png", large))
+		if recorder.Header().Get("Content-Encoding") != "" || recorder.Body.String() != large {
+			t.Error("Expected image/png to stay uncompressed")
+		}
+		if recorder.Header().Get("Vary") != "" {
+			t.Error("Expected no Vary for an ineligible content type")
+		}
+	})
+
+	t.Run("sniffed_content_type", func(t *testing.T) {
+		recorder := serveCompressed(NewCompress(), compressRequest("GET", "gzip"), writeBody("", "<html>"+large+"</html>"))
+		if recorder.Header().Get("Content-Encoding") != EncodingGzip {
+			t.Errorf("Expected sniffed text/html to be compressed, got %v", recorder.Header())
+		}
+	})
+
+	t.Run("already_encoded", func(t *testing.T) {
+		recorder := serveCompressed(NewCompress(), compressRequest("GET", "gzip"), func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/plain")
+			w.Header().Set("Content-Encoding", "br")
+			_, _ = io.WriteString(w, large)
+		})
+		if recorder.Header().Get("Content-Encoding") != "br" || recorder.Body.String() != large {
+			t.Error("Expected pre-encoded response to pass through")
+		}
+	})
+
+	t.Run("identity_requested", func(t *testing.T) {
+		recorder := serveCompressed(NewCompress(), compressRequest("GET", ""), writeBody("text/plain", large))
+		if recorder.Header().Get("Content-Encoding") != "" || recorder.Body.String() != large {
+			t.Error("Expected no compression without Accept-Encoding")
+		}
+		if recorder.Header().Get("Vary") != "Accept-Encoding" {
+			t.Errorf("Expected Vary on an eligible identity response, got %q", recorder.Header().Get("Vary"))
+		}
+
+		recorder = serveCompressed(NewCompress(), compressRequest("GET", "identity"), writeBody("image/png", large))
+		if recorder.Header().Get("Vary") != "" || recorder.Body.String() != large {
+			t.Error("Expected no Vary for an ineligible identity response")
+		}
+	})
+
+	t.Run("empty_body_with_zero_min_size", func(t *testing.T) {
+		compress := NewCompress()
+		compress.MinSize = 0
+		for _, handler := range []func(w http.ResponseWriter, r *http.Request){
+			writeBody("text/plain", ""),
+			func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/plain")
+				w.WriteHeader(http.StatusOK)
+				w.(http.Flusher).Flush()
+			},
+		} {
+			recorder := serveCompressed(compress, compressRequest("GET", "gzip"), handler)
+			if recorder.Header().Get("Content-Encoding") != "" || recorder.Body.Len() != 0 {
+				t.Errorf("Expected an empty body to stay uncompressed, got %v %q", recorder.Header(), recorder.Body.String())
+			}
+		}
+		recorder := serveCompressed(compress, compressRequest("GET", "gzip"), writeBody("text/plain", "a"))
+		if recorder.Header().Get("Content-Encoding") != EncodingGzip {
+			t.Errorf("Expected a one byte body to be compressed with MinSize 0, got %v", recorder.Header())
+		}
+	})
+
+	t.Run("head", func(t *testing.T) {
+		recorder := serveCompressed(NewCompress(), compressRequest("HEAD", "gzip"), func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/plain")
+			w.Header().Set("Content-Length", "5000")
+			w.WriteHeader(http.StatusOK)
+		})
+		if recorder.Header().Get("Content-Encoding") != "" || recorder.Header().Get("Content-Length") != "5000" {
+			t.Errorf("Expected HEAD headers untouched, got %v", recorder.Header())
+		}
+	})
+
+	t.Run("no_body_statuses", func(t *testing.T) {
+		for _, status := range []int{http.StatusNoContent, http.StatusNotModified} {
+			recorder := serveCompressed(NewCompress(), compressRequest("GET", "gzip"), func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/plain")
+				w.Header().Set("ETag", `"v1"`)
+				w.WriteHeader(status)
+			})
+			if recorder.Code != status || recorder.Header().Get("Content-Encoding") != "" || recorder.Body.Len() != 0 {
+				t.Errorf("Status %d: expected empty uncompressed response, got %v", status, recorder.Header())
+			}
+			if recorder.Header().Get("ETag") != `"v1"` {
+				t.Errorf("Status %d: expected ETag untouched, got %q", status, recorder.Header().Get("ETag"))
+			}
+		}
+	})
+
+	t.Run("status_and_etag", func(t *testing.T) {
+		recorder := serveCompressed(NewCompress(), compressRequest("GET", "gzip"), func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/plain")
+			w.Header().Set("Content-Length", "5000")
+			w.Header().Set("ETag", `"v1"`)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = io.WriteString(w, strings.Repeat("x", 5000))
+		})
+		if recorder.Code != http.StatusCreated {
+			t.Errorf("Expected 201, got %d", recorder.Code)
+		}
+		if recorder.Header().Get("Content-Length") != "" || recorder.Header().Get("ETag") != `W/"v1"` {
+			t.Errorf("Expected Content-Length removed and weak ETag, got %v", recorder.Header())
+		}
+	})
+
+	t.Run("flush", func(t *testing.T) {
+		recorder := serveCompressed(NewCompress(), compressRequest("GET", "gzip"), func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = io.WriteString(w, "data: 1\n\n")
+			if err := http.NewResponseController(w).Flush(); err != nil {
+				t.Errorf("Flush failed: %v", err)
+			}
+			if !headerSent(w) {
+				t.Error("Expected header to be sent after Flush")
+			}
+		})
+		if !recorder.Flushed || recorder.Body.String() != "data: 1\n\n" {
+			t.Errorf("Expected flushed uncompressed event, got %q", recorder.Body.String())
+		}
+	})
+
+	t.Run("panic_leaves_response_unwritten", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		func() {
+			defer func() { _ = recover() }()
+			NewCompress().GetHandler()(func(w http.ResponseWriter, r *http.Request, params *Param.Params) {
+				_, _ = io.WriteString(w, "partial")
+				panic("boom")
+			})(recorder, compressRequest("GET", "gzip"), nil)
+		}()
+		if recorder.Code != http.StatusOK || recorder.Body.Len() != 0 || bytes.Contains(recorder.Body.Bytes(), []byte("partial")) {
+			t.Errorf("Expected buffered body to be dropped, got %q", recorder.Body.String())
+		}
+	})
+}
+
+// headerSent reports whether the compress writer has sent the header downstream.
+func headerSent(w http.ResponseWriter) bool {
+	return w.(Recorder).Recorder().Unwrap().(*compressWriter).decided
+}
+
+func TestCompressWriterInterfaces(t *testing.T) {
+	for mask := 0; mask < 16; mask++ {
+		full := &fullWriter{ResponseRecorder: httptest.NewRecorder()}
+		NewCompress().GetHandler()(func(w http.ResponseWriter, r *http.Request, params *Param.Params) {
+			_, isFlusher := w.(http.Flusher)
+			_, isHijacker := w.(http.Hijacker)
+			_, isReaderFrom := w.(io.ReaderFrom)
+			_, isPusher := w.(http.Pusher)
+			if isFlusher != (mask&supportsFlusher != 0) || isHijacker != (mask&supportsHijacker != 0) ||
+				isReaderFrom != (mask&supportsReaderFrom != 0) || isPusher != (mask&supportsPusher != 0) {
+				t.Errorf("mask %04b: unexpected interfaces flusher=%v hijacker=%v readerFrom=%v pusher=%v",
+					mask, isFlusher, isHijacker, isReaderFrom, isPusher)
+			}
+		})(writerWithMask(full, mask), compressRequest("GET", "gzip"), nil)
+	}
+
+	t.Run("read_from_compresses", func(t *testing.T) {
+		full := &fullWriter{ResponseRecorder: httptest.NewRecorder()}
+		body := strings.Repeat("compressible ", 200)
+		NewCompress().GetHandler()(func(w http.ResponseWriter, r *http.Request, params *Param.Params) {
+			w.Header().Set("Content-Type", "text/plain")
+			_, _ = w.(io.ReaderFrom).ReadFrom(strings.NewReader(body))
+		})(full, compressRequest("GET", "gzip"), nil)
+		if full.readFrom || full.Header().Get("Content-Encoding") != EncodingGzip {
+			t.Fatalf("Expected ReadFrom to go through the compressor, got %v", full.Header())
+		}
+		reader, err := gzip.NewReader(full.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		decoded, _ := io.ReadAll(reader)
+		if string(decoded) != body {
+			t.Errorf("Unexpected decompressed body length %d", len(decoded))
+		}
+	})
+
+	t.Run("hijack", func(t *testing.T) {
+		full := &fullWriter{ResponseRecorder: httptest.NewRecorder()}
+		NewCompress().GetHandler()(func(w http.ResponseWriter, r *http.Request, params *Param.Params) {
+			if _, _, err := w.(http.Hijacker).Hijack(); err != nil {
+				t.Errorf("Expected hijack before writing to succeed, got %v", err)
+			}
+		})(full, compressRequest("GET", "gzip"), nil)
+		if !full.hijacked || full.Body.Len() != 0 {
+			t.Error("Expected hijacked connection without a response")
+		}
+	})
+
+	t.Run("hijack_mid_stream", func(t *testing.T) {
+		full := &fullWriter{ResponseRecorder: httptest.NewRecorder()}
+		NewCompress().GetHandler()(func(w http.ResponseWriter, r *http.Request, params *Param.Params) {
+			w.Header().Set("Content-Type", "text/plain")
+			_, _ = io.WriteString(w, strings.Repeat("x", 2*DefaultCompressMinSize))
+			if _, _, err := w.(http.Hijacker).Hijack(); err != ErrHijackCompressed {
+				t.Errorf("Expected ErrHijackCompressed, got %v", err)
+			}
+		})(full, compressRequest("GET", "gzip"), nil)
+		if full.hijacked {
+			t.Error("Expected underlying connection not to be hijacked")
+		}
+		reader, err := gzip.NewReader(full.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if decoded, _ := io.ReadAll(reader); len(decoded) != 2*DefaultCompressMinSize {
+			t.Errorf("Expected complete gzip stream, got %d bytes", len(decoded))
+		}
+	})
+}
@@ -0,0 +1,225 @@
+package Middleware
+
+import (
+	"LiteFrame/Router/Param"
+	"LiteFrame/Router/Types"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// ======================
+// Concurrency Limit Tests
+// ======================
+
+// blockingServer runs requests through a ConcurrencyLimit whose handler blocks until released.
+type blockingServer struct {
+	handler Types.HandlerFunc
+	release chan struct{}
+	entered chan string
+}
+
+func newBlockingServer(limiter *ConcurrencyLimit) *blockingServer {
+	server := &blockingServer{release: make(chan struct{}), entered: make(chan string, 16)}
+	server.handler = limiter.GetHandler()(func(w http.ResponseWriter, r *http.Request, params *Param.Params) {
+		server.entered <- r.URL.Path
+		<-server.release
+		w.WriteHeader(http.StatusOK)
+	})
+	return server
+}
+
+// serve starts a request in the background and returns a channel receiving its status.
+func (instance *blockingServer) serve(path string, pattern string) chan int {
+	result := make(chan int, 1)
+	request := httptest.NewRequest("GET", path, nil)
+	request.Pattern = pattern
+	go func() {
+		recorder := httptest.NewRecorder()
+		instance.handler(recorder, request, nil)
+		result <- recorder.Code
+	}()
+	return result
+}
+
+// queued waits until the limiter has count waiters.
+func queued(t *testing.T, limiter *concurrencyLimiter, count int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		limiter.mutex.Lock()
+		current := limiter.queued
+		limiter.mutex.Unlock()
+		if current == count {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("Expected %d queued requests", count)
+}
+
+func TestConcurrencyLimit(t *testing.T) {
+	t.Run("shed_when_queue_full", func(t *testing.T) {
+		limiter := NewConcurrencyLimit(1)
+		limiter.QueueSize = 1
+		server := newBlockingServer(limiter)
+
+		first := server.serve("/1", "")
+		<-server.entered
+		second := server.serve("/2", "")
+		queued(t, limiter.global, 1)
+		if code := <-server.serve("/3", ""); code != http.StatusServiceUnavailable {
+			t.Errorf("Expected third request to be shed with 503, got %d", code)
+		}
+
+		server.release <- struct{}{}
+		if <-first != http.StatusOK || <-server.entered != "/2" {
+			t.Fatal("Expected queued request to run after the first completed")
+		}
+		server.release <- struct{}{}
+		if <-second != http.StatusOK {
+			t.Error("Expected queued request to succeed")
+		}
+	})
+
+	t.Run("queue_timeout", func(t *testing.T) {
+		limiter := NewConcurrencyLimit(1)
+		limiter.QueueTimeout = 10 * time.Millisecond
+		server := newBlockingServer(limiter)
+		first := server.serve("/1", "")
+		<-server.entered
+		if code := <-server.serve("/2", ""); code != http.StatusServiceUnavailable {
+			t.Errorf("Expected timed out waiter to get 503, got %d", code)
+		}
+		close(server.release)
+		<-first
+		if limiter.global.queued != 0 || limiter.global.inFlight != 0 {
+			t.Errorf("Expected empty limiter, got queued=%d inFlight=%d", limiter.global.queued, limiter.global.inFlight)
+		}
+	})
+
+	t.Run("priority_order_and_displacement", func(t *testing.T) {
+		limiter := NewConcurrencyLimit(1)
+		limiter.QueueSize = 2
+		limiter.Priorities = map[string]Priority{"/low": PriorityLow, "/critical": PriorityCritical}
+		server := newBlockingServer(limiter)
+
+		running := server.serve("/running", "")
+		<-server.entered
+		normal := server.serve("/normal", "")
+		queued(t, limiter.global, 1)
+		low := server.serve("/low", "/low")
+		queued(t, limiter.global, 2)
+		critical := server.serve("/critical", "/critical")
+
+		if code := <-low; code != http.StatusServiceUnavailable {
+			t.Errorf("Expected low priority waiter to be displaced, got %d", code)
+		}
+		server.release <- struct{}{}
+		<-running
+		if path := <-server.entered; path != "/critical" {
+			t.Errorf("Expected critical request to run first, got %s", path)
+		}
+		server.release <- struct{}{}
+		if path := <-server.entered; path != "/normal" {
+			t.Errorf("Expected normal request next, got %s", path)
+		}
+		server.release <- struct{}{}
+		<-critical
+		<-normal
+	})
+
+	t.Run("per_route_cap", func(t *testing.T) {
+		limiter := &ConcurrencyLimit{Routes: map[string]int{"/reports/:id": 1}}
+		server := newBlockingServer(limiter)
+		report := server.serve("/reports/1", "/reports/:id")
+		<-server.entered
+		if code := <-server.serve("/reports/2", "/reports/:id"); code != http.StatusServiceUnavailable {
+			t.Errorf("Expected second report to be shed, got %d", code)
+		}
+		other := server.serve("/other", "/other")
+		if path := <-server.entered; path != "/other" {
+			t.Errorf("Expected other routes to be unaffected, got %s", path)
+		}
+		close(server.release)
+		<-report
+		<-other
+	})
+
+	t.Run("shared_between_registrations", func(t *testing.T) {
+		limiter := NewConcurrencyLimit(3)
+		limiter.GetHandler()
+		global := limiter.global
+		limiter.GetHandler()
+		if limiter.global != global {
+			t.Error("Expected every GetHandler call to share the same limiter")
+		}
+	})
+}
+
+func TestConcurrencyLimiterAIMD(t *testing.T) {
+	limiter := newConcurrencyLimiter(10, 0, &AIMD{Target: 100 * time.Millisecond, Decrease: 0.5, Min: 2, Max: 12})
+	for index := 0; index < 3; index++ {
+		if !limiter.acquire(context.Background(), PriorityNormal) {
+			t.Fatal("Expected slot")
+		}
+	}
+
+	limiter.release(time.Second)
+	if limiter.current() != 5 {
+		t.Errorf("Expected limit halved to 5, got %d", limiter.current())
+	}
+	limiter.release(time.Second)
+	if limiter.current() != 5 {
+		t.Errorf("Expected at most one decrease per Target, got %d", limiter.current())
+	}
+	limiter.lastDecrease = time.Time{}
+	limiter.release(time.Second)
+	limiter.lastDecrease = time.Time{}
+	limiter.inFlight = 1
+	limiter.release(time.Second)
+	if limiter.current() != 2 {
+		t.Errorf("Expected limit bounded by Min 2, got %d", limiter.current())
+	}
+
+	for index := 0; index < 200; index++ {
+		limiter.inFlight = 1
+		limiter.release(time.Millisecond)
+	}
+	if limiter.current() != 12 {
+		t.Errorf("Expected limit to grow back to Max 12, got %d", limiter.current())
+	}
+}
+
+func TestConcurrencyLimitRace(t *testing.T) {
+	limiter := NewConcurrencyLimit(4)
+	limiter.QueueSize = 100
+	limiter.Adaptive = &AIMD{Target: time.Millisecond}
+	var mutex sync.Mutex
+	inFlight, peak := 0, 0
+	handler := limiter.GetHandler()(func(w http.ResponseWriter, r *http.Request, params *Param.Params) {
+		mutex.Lock()
+		inFlight++
+		peak = max(peak, inFlight)
+		mutex.Unlock()
+		time.Sleep(100 * time.Microsecond)
+		mutex.Lock()
+		inFlight--
+		mutex.Unlock()
+	})
+	var group sync.WaitGroup
+	for index := 0; index < 50; index++ {
+		group.Add(1)
+		go func() {
+			defer group.Done()
+			handler(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil), nil)
+		}()
+	}
+	group.Wait()
+	if peak > 4 {
+		t.Errorf("Expected at most 4 concurrent handlers, got %d", peak)
+	}
+}
@@ -0,0 +1,204 @@
+package Middleware
+
+import (
+	"LiteFrame/Router/Param"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// ======================
+// CORS Middleware Tests
+// ======================
+
+// staticMethods is a MethodLister with a fixed method set per path.
+type staticMethods map[string][]string
+
+func (instance staticMethods) AllowedMethods(path string) []string {
+	return instance[path]
+}
+
+func serveCORS(cors *CORS, request *http.Request) (*httptest.ResponseRecorder, bool) {
+	reached := false
+	recorder := httptest.NewRecorder()
+	cors.GetHandler()(func(w http.ResponseWriter, r *http.Request, params *Param.Params) {
+		reached = true
+	})(recorder, request, nil)
+	return recorder, reached
+}
+
+func preflightRequest(path string, origin string, method string, headers string) *http.Request {
+	request := httptest.NewRequest(http.MethodOptions, path, nil)
+	request.Header.Set("Origin", origin)
+	request.Header.Set("Access-Control-Request-Method", method)
+	if headers != "" {
+		request.Header.Set("Access-Control-Request-Headers", headers)
+	}
+	return request
+}
+
+func TestCORSOrigins(t *testing.T) {
+	cors := &CORS{
+		AllowedOrigins: []string{"https://app.example.com", "https://*.example.org"},
+		AllowOriginFunc: func(origin string, request *http.Request) bool {
+			return origin == "http://localhost:3000"
+		},
+	}
+	tests := []struct {
+		origin  string
+		allowed bool
+	}{
+		{"https://app.example.com", true},
+		{"HTTPS://APP.EXAMPLE.COM", true},
+		{"https://evil.example.com", false},
+		{"https://api.example.org", true},
+		{"https://a.b.example.org", true},
+		{"https://example.org", false},
+		{"http://api.example.org", false},
+		{"https://api.example.org.evil.com", false},
+		{"http://localhost:3000", true},
+	}
+	for _, test := range tests {
+		request := httptest.NewRequest("GET", "/", nil)
+		request.Header.Set("Origin", test.origin)
+		recorder, reached := serveCORS(cors, request)
+		if !reached {
+			t.Errorf("%s: expected simple request to reach the handler", test.origin)
+		}
+		got := recorder.Header().Get("Access-Control-Allow-Origin")
+		if (got == test.origin) != test.allowed {
+			t.Errorf("%s: expected allowed=%v, got Access-Control-Allow-Origin %q", test.origin, test.allowed, got)
+		}
+		if recorder.Header().Get("Vary") != "Origin" {
+			t.Errorf("%s: expected Vary: Origin, got %q", test.origin, recorder.Header().Get("Vary"))
+		}
+	}
+}
+
+func TestCORSSimpleRequest(t *testing.T) {
+	t.Run("any_origin", func(t *testing.T) {
+		cors := NewCORS(nil)
+		cors.ExposedHeaders = []string{"X-Total-Count", "ETag"}
+		request := httptest.NewRequest("GET", "/", nil)
+		request.Header.Set("Origin", "https://site.test")
+		recorder, _ := serveCORS(cors, request)
+		header := recorder.Header()
+		if header.Get("Access-Control-Allow-Origin") != "*" {
+			t.Errorf("Expected *, got %q", header.Get("Access-Control-Allow-Origin"))
+		}
+		if header.Get("Access-Control-Expose-Headers") != "X-Total-Count, ETag" {
+			t.Errorf("Unexpected exposed headers %q", header.Get("Access-Control-Expose-Headers"))
+		}
+		if header.Get("Vary") != "" {
+			t.Errorf("Expected no Vary for a wildcard origin, got %q", header.Get("Vary"))
+		}
+	})
+
+	t.Run("credentials_reflect_origin", func(t *testing.T) {
+		cors := NewCORS(nil)
+		cors.AllowedOrigins = []string{"https://site.test"}
+		cors.AllowCredentials = true
+		request := httptest.NewRequest("GET", "/", nil)
+		request.Header.Set("Origin", "https://site.test")
+		recorder, _ := serveCORS(cors, request)
+		if recorder.Header().Get("Access-Control-Allow-Origin") != "https://site.test" ||
+			recorder.Header().Get("Access-Control-Allow-Credentials") != "true" {
+			t.Errorf("Expected reflected origin with credentials, got %v", recorder.Header())
+		}
+	})
+
+	t.Run("no_credentials_for_any_origin", func(t *testing.T) {
+		cors := NewCORS(nil)
+		cors.AllowedOrigins = []string{"*", "https://app.test"}
+		cors.AllowCredentials = true
+		request := httptest.NewRequest("GET", "/", nil)
+		request.Header.Set("Origin", "https://evil.test")
+		recorder, _ := serveCORS(cors, request)
+		if recorder.Header().Get("Access-Control-Allow-Origin") != "*" ||
+			recorder.Header().Get("Access-Control-Allow-Credentials") != "" {
+			t.Errorf("Expected * without credentials, got %v", recorder.Header())
+		}
+
+		request.Header.Set("Origin", "https://app.test")
+		recorder, _ = serveCORS(cors, request)
+		if recorder.Header().Get("Access-Control-Allow-Origin") != "https://app.test" ||
+			recorder.Header().Get("Access-Control-Allow-Credentials") != "true" {
+			t.Errorf("Expected explicit origin with credentials, got %v", recorder.Header())
+		}
+		if recorder.Header().Get("Vary") != "Origin" {
+			t.Errorf("Expected Vary: Origin, got %q", recorder.Header().Get("Vary"))
+		}
+	})
+
+	t.Run("no_origin", func(t *testing.T) {
+		recorder, reached := serveCORS(NewCORS(nil), httptest.NewRequest("GET", "/", nil))
+		if !reached || recorder.Header().Get("Access-Control-Allow-Origin") != "" {
+			t.Error("Expected same-origin request to pass through without CORS headers")
+		}
+	})
+}
+
+func TestCORSPreflight(t *testing.T) {
+	methods := staticMethods{"/users/42": {"GET", "PUT", "DELETE"}}
+	cors := NewCORS(methods)
+	cors.MaxAge = 10 * time.Minute
+
+	t.Run("registered_methods", func(t *testing.T) {
+		recorder, reached := serveCORS(cors, preflightRequest("/users/42", "https://site.test", "PUT", "content-type, x-api-key"))
+		if reached {
+			t.Error("Expected preflight to be answered by the middleware")
+		}
+		header := recorder.Header()
+		if recorder.Code != http.StatusNoContent {
+			t.Errorf("Expected 204, got %d", recorder.Code)
+		}
+		if header.Get("Access-Control-Allow-Methods") != "GET, PUT, DELETE" {
+			t.Errorf("Unexpected methods %q", header.Get("Access-Control-Allow-Methods"))
+		}
+		if header.Get("Access-Control-Allow-Headers") != "Content-Type, X-Api-Key" {
+			t.Errorf("Unexpected headers %q", header.Get("Access-Control-Allow-Headers"))
+		}
+		if header.Get("Access-Control-Max-Age") != "600" {
+			t.Errorf("Unexpected max age %q", header.Get("Access-Control-Max-Age"))
+		}
+		if vary := strings.Join(header.Values("Vary"), ","); vary != "Origin,Access-Control-Request-Method,Access-Control-Request-Headers" {
+			t.Errorf("Unexpected Vary %q", vary)
+		}
+	})
+
+	t.Run("unregistered_method", func(t *testing.T) {
+		recorder, _ := serveCORS(cors, preflightRequest("/users/42", "https://site.test", "PATCH", ""))
+		if recorder.Code != http.StatusNoContent || recorder.Header().Get("Access-Control-Allow-Origin") != "" {
+			t.Errorf("Expected 204 without CORS headers, got %d %v", recorder.Code, recorder.Header())
+		}
+	})
+
+	t.Run("unknown_route", func(t *testing.T) {
+		_, reached := serveCORS(cors, preflightRequest("/missing", "https://site.test", "GET", ""))
+		if !reached {
+			t.Error("Expected preflight for unknown route to reach the next handler")
+		}
+	})
+
+	t.Run("header_allow_list", func(t *testing.T) {
+		restricted := &CORS{AllowedOrigins: []string{"*"}, AllowedMethods: []string{"POST"}, AllowedHeaders: []string{"content-type"}}
+		recorder, _ := serveCORS(restricted, preflightRequest("/", "https://site.test", "POST", "Content-Type"))
+		if recorder.Header().Get("Access-Control-Allow-Headers") != "Content-Type" {
+			t.Errorf("Expected allowed header, got %v", recorder.Header())
+		}
+		recorder, _ = serveCORS(restricted, preflightRequest("/", "https://site.test", "POST", "X-Secret"))
+		if recorder.Header().Get("Access-Control-Allow-Origin") != "" {
+			t.Errorf("Expected disallowed header to fail the preflight, got %v", recorder.Header())
+		}
+	})
+
+	t.Run("plain_options_passthrough", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodOptions, "/users/42", nil)
+		request.Header.Set("Origin", "https://site.test")
+		if _, reached := serveCORS(cors, request); !reached {
+			t.Error("Expected OPTIONS without Access-Control-Request-Method to reach the handler")
+		}
+	})
+}
@@ -0,0 +1,164 @@
+package Middleware
+
+import (
+	"LiteFrame/Router/Param"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// ======================
+// CSRF Test Helpers
+// ======================
+
+// serveCSRF runs request through csrf and returns the response and the token seen by the handler.
+func serveCSRF(csrf *CSRF, request *http.Request) (*httptest.ResponseRecorder, string) {
+	token := ""
+	recorder := httptest.NewRecorder()
+	csrf.GetHandler()(func(w http.ResponseWriter, r *http.Request, params *Param.Params) {
+		token = GetCSRFToken(r.Context())
+	})(recorder, request, nil)
+	return recorder, token
+}
+
+// csrfSession performs a GET and returns the issued cookie and token.
+func csrfSession(t *testing.T, csrf *CSRF) (*http.Cookie, string) {
+	t.Helper()
+	recorder, token := serveCSRF(csrf, httptest.NewRequest("GET", "https://example.com/form", nil))
+	cookies := recorder.Result().Cookies()
+	if len(cookies) != 1 || token == "" {
+		t.Fatalf("Expected cookie and token, got %v %q", cookies, token)
+	}
+	return cookies[0], token
+}
+
+// ======================
+// CSRF Tests
+// ======================
+
+func TestCSRFCookie(t *testing.T) {
+	csrf := NewCSRF()
+	cookie, token := csrfSession(t, csrf)
+	if cookie.Name != DefaultCSRFCookieName || !cookie.Secure || cookie.SameSite != http.SameSiteLaxMode || cookie.MaxAge != 43200 {
+		t.Errorf("Unexpected cookie %+v", cookie)
+	}
+
+	// An existing cookie is reused, with a differently masked token
+	request := httptest.NewRequest("GET", "https://example.com/form", nil)
+	request.AddCookie(cookie)
+	recorder, second := serveCSRF(csrf, request)
+	if len(recorder.Result().Cookies()) != 0 {
+		t.Error("Expected no new cookie when one is present")
+	}
+	if second == token {
+		t.Error("Expected a freshly masked token per request")
+	}
+}
+
+func TestCSRFValidation(t *testing.T) {
+	csrf := NewCSRF()
+	csrf.TrustedOrigins = []string{"https://*.partner.com"}
+	cookie, token := csrfSession(t, csrf)
+
+	post := func(body string, headers map[string]string, withCookie bool) int {
+		request := httptest.NewRequest("POST", "https://example.com/form", strings.NewReader(body))
+		if body != "" {
+			request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+		for name, value := range headers {
+			request.Header.Set(name, value)
+		}
+		if withCookie {
+			request.AddCookie(cookie)
+		}
+		recorder, _ := serveCSRF(csrf, request)
+		return recorder.Code
+	}
+
+	tests := []struct {
+		name       string
+		body       string
+		headers    map[string]string
+		withCookie bool
+		expected   int
+	}{
+		{"header_token", "", map[string]string{"X-CSRF-Token": token}, true, http.StatusOK},
+		{"form_token", url.Values{"csrf_token": {token}}.Encode(), nil, true, http.StatusOK},
+		{"raw_cookie_value", "", map[string]string{"X-CSRF-Token": cookie.Value}, true, http.StatusOK},
+		{"missing_token", "", nil, true, http.StatusForbidden},
+		{"wrong_token", "", map[string]string{"X-CSRF-Token": base64.RawURLEncoding.EncodeToString(make([]byte, 64))}, true, http.StatusForbidden},
+		{"garbage_token", "", map[string]string{"X-CSRF-Token": "!!"}, true, http.StatusForbidden},
+		{"missing_cookie", "", map[string]string{"X-CSRF-Token": token}, false, http.StatusForbidden},
+		{"fetch_same_origin", "", map[string]string{"X-CSRF-Token": token, "Sec-Fetch-Site": "same-origin"}, true, http.StatusOK},
+		{"fetch_cross_site", "", map[string]string{"X-CSRF-Token": token, "Sec-Fetch-Site": "cross-site"}, true, http.StatusForbidden},
+		{"fetch_same_site", "", map[string]string{"X-CSRF-Token": token, "Sec-Fetch-Site": "same-site"}, true, http.StatusForbidden},
+		{"origin_match", "", map[string]string{"X-CSRF-Token": token, "Origin": "https://example.com"}, true, http.StatusOK},
+		{"origin_mismatch", "", map[string]string{"X-CSRF-Token": token, "Origin": "https://evil.com"}, true, http.StatusForbidden},
+		{"origin_scheme_mismatch", "", map[string]string{"X-CSRF-Token": token, "Origin": "http://example.com"}, true, http.StatusForbidden},
+		{"trusted_origin", "", map[string]string{"X-CSRF-Token": token, "Origin": "https://app.partner.com", "Sec-Fetch-Site": "cross-site"}, true, http.StatusOK},
+		{"trusted_origin_needs_token", "", map[string]string{"Origin": "https://app.partner.com"}, true, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code := post(tt.body, tt.headers, tt.withCookie); code != tt.expected {
+				t.Errorf("Expected %d, got %d", tt.expected, code)
+			}
+		})
+	}
+}
+
+func TestCSRFSafeMethods(t *testing.T) {
+	for _, method := range []string{"GET", "HEAD", "OPTIONS", "TRACE"} {
+		request := httptest.NewRequest(method, "https://example.com/", nil)
+		request.Header.Set("Sec-Fetch-Site", "cross-site")
+		if recorder, _ := serveCSRF(NewCSRF(), request); recorder.Code != http.StatusOK {
+			t.Errorf("%s: expected safe method to pass, got %d", method, recorder.Code)
+		}
+	}
+}
+
+func TestCSRFSynchronizer(t *testing.T) {
+	sessions := map[string]string{"s1": "session-secret"}
+	csrf := NewCSRF()
+	csrf.Secret = func(request *http.Request) (string, error) {
+		cookie, err := request.Cookie("session")
+		if err != nil {
+			return "", nil
+		}
+		if cookie.Value == "broken" {
+			return "", errors.New("session store down")
+		}
+		return sessions[cookie.Value], nil
+	}
+	request := func(method string, session string, token string) (*httptest.ResponseRecorder, string) {
+		request := httptest.NewRequest(method, "https://example.com/", nil)
+		if session != "" {
+			request.AddCookie(&http.Cookie{Name: "session", Value: session})
+		}
+		if token != "" {
+			request.Header.Set("X-CSRF-Token", token)
+		}
+		return serveCSRF(csrf, request)
+	}
+
+	recorder, token := request("GET", "s1", "")
+	if token == "" || len(recorder.Result().Cookies()) != 0 {
+		t.Fatalf("Expected token without cookie, got %q %v", token, recorder.Result().Cookies())
+	}
+	if recorder, _ = request("POST", "s1", token); recorder.Code != http.StatusOK {
+		t.Errorf("Expected valid synchronizer token, got %d", recorder.Code)
+	}
+	if recorder, _ = request("POST", "s2", token); recorder.Code != http.StatusForbidden {
+		t.Errorf("Expected rejection without session secret, got %d", recorder.Code)
+	}
+	if recorder, token = request("GET", "", ""); recorder.Code != http.StatusOK || token != "" {
+		t.Errorf("Expected safe request without token, got %d %q", recorder.Code, token)
+	}
+	if recorder, _ = request("GET", "broken", ""); recorder.Code != http.StatusInternalServerError {
+		t.Errorf("Expected 500 on secret failure, got %d", recorder.Code)
+	}
+}
@@ -0,0 +1,46 @@
+package Middleware
+
+import (
+	"LiteFrame/Router/Error"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// ======================
+// Shared Error Handler Tests
+// ======================
+
+func TestHandleError(t *testing.T) {
+	err := Error.NewErrorWithCode(Error.Forbidden, "/")
+	teapot := func(w http.ResponseWriter, r *http.Request, err error) {
+		w.WriteHeader(http.StatusTeapot)
+	}
+	accepted := func(w http.ResponseWriter, r *http.Request, err error) {
+		w.WriteHeader(http.StatusAccepted)
+	}
+
+	tests := []struct {
+		name    string
+		handler func(http.ResponseWriter, *http.Request, error)
+		tree    func(http.ResponseWriter, *http.Request, error)
+		status  int
+	}{
+		{"default", nil, nil, http.StatusForbidden},
+		{"tree_handler", nil, teapot, http.StatusTeapot},
+		{"own_handler_wins", accepted, teapot, http.StatusAccepted},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request := httptest.NewRequest("GET", "/", nil)
+			if test.tree != nil {
+				request = request.WithContext(WithErrorHandler(request.Context(), test.tree))
+			}
+			recorder := httptest.NewRecorder()
+			HandleError(recorder, request, err, test.handler)
+			if recorder.Code != test.status {
+				t.Errorf("Expected %d, got %d", test.status, recorder.Code)
+			}
+		})
+	}
+}
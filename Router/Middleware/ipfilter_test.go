@@ -0,0 +1,240 @@
+package Middleware
+
+import (
+	"LiteFrame/Router/Param"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// ======================
+// Prefix Set Tests
+// ======================
+
+func TestPrefixSet(t *testing.T) {
+	prefixes, err := ParsePrefixes([]string{"10.0.0.0/8", "10.1.0.0/16", "192.0.2.7", "2001:db8::/32", "::ffff:198.51.100.0/120"})
+	if err != nil {
+		t.Fatalf("ParsePrefixes: %v", err)
+	}
+	set := NewPrefixSet(prefixes)
+	if set.Len() != 4 {
+		t.Errorf("Expected 4 prefixes (10.1.0.0/16 covered), got %d", set.Len())
+	}
+
+	tests := map[string]bool{
+		"10.0.0.1":          true,
+		"10.255.255.255":    true,
+		"11.0.0.0":          false,
+		"192.0.2.7":         true,
+		"192.0.2.8":         false,
+		"198.51.100.42":     true,
+		"::ffff:10.2.3.4":   true,
+		"2001:db8:1::1":     true,
+		"2001:db9::1":       false,
+		"::a00:1":           false, // IPv4-compatible, not IPv4
+		"fe80::1%eth0":      false,
+		"2001:db8::1%eth0":  true,
+		"0.0.0.0":           false,
+		"ffff:ffff::ffff:1": false,
+	}
+	for value, expected := range tests {
+		if got := set.Contains(netip.MustParseAddr(value)); got != expected {
+			t.Errorf("%s: expected %v, got %v", value, expected, got)
+		}
+	}
+	if set.Contains(netip.Addr{}) {
+		t.Error("Expected invalid address not to match")
+	}
+}
+
+func TestPrefixSetCoverage(t *testing.T) {
+	// Narrow prefixes inserted before a broader one are folded into it
+	set := NewPrefixSet([]netip.Prefix{
+		netip.MustParsePrefix("10.1.2.0/24"),
+		netip.MustParsePrefix("10.1.3.0/24"),
+		netip.MustParsePrefix("10.0.0.0/8"),
+		netip.MustParsePrefix("::/0"),
+	})
+	if set.Len() != 2 {
+		t.Errorf("Expected 2 prefixes, got %d", set.Len())
+	}
+	if !set.Contains(netip.MustParseAddr("10.9.9.9")) || !set.Contains(netip.MustParseAddr("2001:db8::1")) {
+		t.Error("Expected broad prefixes to match")
+	}
+	var empty *PrefixSet
+	if empty.Contains(netip.MustParseAddr("10.0.0.1")) || empty.Len() != 0 {
+		t.Error("Expected nil set to be empty")
+	}
+}
+
+func TestPrefixSetLarge(t *testing.T) {
+	prefixes := make([]netip.Prefix, 0, 1<<16)
+	for index := 0; index < 1<<16; index++ {
+		prefixes = append(prefixes, netip.PrefixFrom(netip.AddrFrom4([4]byte{100, byte(index >> 8), byte(index), 0}), 24))
+	}
+	set := NewPrefixSet(prefixes)
+	if set.Len() != 1<<16 {
+		t.Fatalf("Expected %d prefixes, got %d", 1<<16, set.Len())
+	}
+	if !set.Contains(netip.MustParseAddr("100.200.17.99")) || set.Contains(netip.MustParseAddr("101.0.0.1")) {
+		t.Error("Unexpected lookup result in large set")
+	}
+}
+
+func TestLoadPrefixes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "office.txt")
+	content := "# office networks\n198.51.100.0/24\n\n2001:db8:100::/48  # Berlin\n192.0.2.1\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	prefixes, err := LoadPrefixes(path)
+	if err != nil {
+		t.Fatalf("LoadPrefixes: %v", err)
+	}
+	if fmt.Sprint(prefixes) != "[198.51.100.0/24 2001:db8:100::/48 192.0.2.1/32]" {
+		t.Errorf("Unexpected prefixes %v", prefixes)
+	}
+
+	if err := os.WriteFile(path, []byte("10.0.0.0/8\nbogus\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadPrefixes(path); err == nil || !strings.HasPrefix(err.Error(), path+":2:") {
+		t.Errorf("Expected error with line number, got %v", err)
+	}
+}
+
+// ======================
+// IP Filter Tests
+// ======================
+
+func TestIPFilter(t *testing.T) {
+	filter, err := NewIPFilter([]string{"10.0.0.0/8", "2001:db8::/32"}, []string{"10.6.6.0/24"})
+	if err != nil {
+		t.Fatalf("NewIPFilter: %v", err)
+	}
+	serve := func(remote string) int {
+		request := httptest.NewRequest("GET", "/admin", nil)
+		request.RemoteAddr = remote
+		recorder := httptest.NewRecorder()
+		filter.GetHandler()(func(w http.ResponseWriter, r *http.Request, params *Param.Params) {})(recorder, request, nil)
+		return recorder.Code
+	}
+
+	tests := map[string]int{
+		"10.1.2.3:1000":        http.StatusOK,
+		"10.6.6.6:1000":        http.StatusForbidden,
+		"203.0.113.1:1000":     http.StatusForbidden,
+		"[2001:db8::5]:1000":   http.StatusOK,
+		"[::ffff:10.1.2.3]:80": http.StatusOK,
+		"@":                    http.StatusForbidden,
+	}
+	for remote, expected := range tests {
+		if code := serve(remote); code != expected {
+			t.Errorf("%s: expected %d, got %d", remote, expected, code)
+		}
+	}
+
+	// An allow list cannot be emptied by a reload
+	if err := filter.Update(nil, []string{"203.0.113.0/24"}); !errors.Is(err, ErrEmptyAllowList) {
+		t.Errorf("Expected ErrEmptyAllowList, got %v", err)
+	}
+	if serve("10.1.2.3:1000") != http.StatusOK || serve("198.51.100.1:1000") != http.StatusForbidden {
+		t.Error("Expected lists unchanged after rejected update")
+	}
+	if err := filter.Update([]string{"198.51.100.0/24"}, []string{"203.0.113.0/24"}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	// Invalid updates keep the current lists
+	if err := filter.Update([]string{"bogus"}, nil); err == nil {
+		t.Error("Expected error for invalid allow list")
+	}
+	if serve("203.0.113.9:1000") != http.StatusForbidden {
+		t.Error("Expected lists unchanged after failed update")
+	}
+	if _, err := NewIPFilter(nil, []string{"10.0.0.0/99"}); err == nil {
+		t.Error("Expected error for invalid deny list")
+	}
+}
+
+func TestIPFilterDenyOnly(t *testing.T) {
+	filter, err := NewIPFilter(nil, []string{"203.0.113.0/24"})
+	if err != nil {
+		t.Fatalf("NewIPFilter: %v", err)
+	}
+	// Deny-only lists admit everything else, including unparseable addresses
+	if !filter.Allowed(netip.MustParseAddr("198.51.100.1")) || !filter.Allowed(netip.Addr{}) || filter.Allowed(netip.MustParseAddr("203.0.113.9")) {
+		t.Error("Unexpected results with deny-only list")
+	}
+	if err := filter.SetLists(nil, nil); err != nil {
+		t.Errorf("Expected empty lists to be accepted, got %v", err)
+	}
+
+	// Without lists nothing is admitted
+	var empty IPFilter
+	if empty.Allowed(netip.MustParseAddr("198.51.100.1")) || empty.Allowed(netip.Addr{}) {
+		t.Error("Expected filter without lists to deny")
+	}
+}
+
+func TestIPFilterReloadEmptyFile(t *testing.T) {
+	filter, _ := NewIPFilter([]string{"198.51.100.0/24"}, nil)
+	path := filepath.Join(t.TempDir(), "office.txt")
+	if err := os.WriteFile(path, []byte("# truncated\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	allow, err := LoadPrefixes(path)
+	if err != nil {
+		t.Fatalf("LoadPrefixes: %v", err)
+	}
+	if err := filter.SetLists(allow, nil); !errors.Is(err, ErrEmptyAllowList) {
+		t.Errorf("Expected ErrEmptyAllowList, got %v", err)
+	}
+	if filter.Allowed(netip.MustParseAddr("203.0.113.1")) || !filter.Allowed(netip.MustParseAddr("198.51.100.7")) {
+		t.Error("Expected previous allow list to stay in place")
+	}
+}
+
+func TestIPFilterRealIP(t *testing.T) {
+	filter, _ := NewIPFilter([]string{"198.51.100.0/24"}, nil)
+	realIP, _ := NewRealIP(HeaderXForwardedFor, "10.0.0.0/8")
+	handler := realIP.GetHandler()(filter.GetHandler()(func(w http.ResponseWriter, r *http.Request, params *Param.Params) {}))
+
+	request := httptest.NewRequest("GET", "/", nil)
+	request.RemoteAddr = "10.0.0.1:1000"
+	request.Header.Set("X-Forwarded-For", "198.51.100.7")
+	recorder := httptest.NewRecorder()
+	handler(recorder, request, nil)
+	if recorder.Code != http.StatusOK {
+		t.Errorf("Expected forwarded client to be allowed, got %d", recorder.Code)
+	}
+}
+
+func TestIPFilterConcurrentReload(t *testing.T) {
+	filter, _ := NewIPFilter([]string{"10.0.0.0/8"}, nil)
+	handler := filter.GetHandler()(func(w http.ResponseWriter, r *http.Request, params *Param.Params) {})
+
+	var wait sync.WaitGroup
+	for worker := 0; worker < 4; worker++ {
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+			for index := 0; index < 200; index++ {
+				request := httptest.NewRequest("GET", "/", nil)
+				request.RemoteAddr = "10.0.0.1:1000"
+				handler(httptest.NewRecorder(), request, nil)
+			}
+		}()
+	}
+	for index := 0; index < 50; index++ {
+		_ = filter.Update([]string{fmt.Sprintf("10.%d.0.0/16", index)}, nil)
+	}
+	wait.Wait()
+}
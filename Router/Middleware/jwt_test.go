@@ -0,0 +1,267 @@
+package Middleware
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// ======================
+// JWT Test Helpers
+// ======================
+
+var (
+	testKeysOnce sync.Once
+	testRSAKey   *rsa.PrivateKey
+	testECKey    *ecdsa.PrivateKey
+	testHMACKey  = []byte("0123456789abcdef0123456789abcdef")
+	testNow      = time.Unix(1_700_000_000, 0)
+)
+
+// testKeys generates the signing keys once for all JWT tests.
+func testKeys(t *testing.T) {
+	t.Helper()
+	testKeysOnce.Do(func() {
+		var err error
+		if testRSAKey, err = rsa.GenerateKey(rand.Reader, 2048); err != nil {
+			panic(err)
+		}
+		if testECKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader); err != nil {
+			panic(err)
+		}
+	})
+}
+
+// encodeSegment base64url-encodes value as JSON.
+func encodeSegment(value any) string {
+	data, _ := json.Marshal(value)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// signJWT creates a compact token signed with algorithm.
+func signJWT(t *testing.T, algorithm string, kid string, claims map[string]any) string {
+	t.Helper()
+	header := map[string]any{"alg": algorithm, "typ": "JWT"}
+	if kid != "" {
+		header["kid"] = kid
+	}
+	signed := encodeSegment(header) + "." + encodeSegment(claims)
+	digest := sha256.Sum256([]byte(signed))
+	var signature []byte
+	switch algorithm {
+	case AlgorithmHS256:
+		mac := hmac.New(sha256.New, testHMACKey)
+		mac.Write([]byte(signed))
+		signature = mac.Sum(nil)
+	case AlgorithmRS256:
+		var err error
+		if signature, err = rsa.SignPKCS1v15(rand.Reader, testRSAKey, crypto.SHA256, digest[:]); err != nil {
+			t.Fatal(err)
+		}
+	case AlgorithmES256:
+		r, s, err := ecdsa.Sign(rand.Reader, testECKey, digest[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		signature = make([]byte, 64)
+		r.FillBytes(signature[:32])
+		s.FillBytes(signature[32:])
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+// writeTestJWKS writes a JWKS document with the test keys and returns its path.
+func writeTestJWKS(t *testing.T) string {
+	t.Helper()
+	encode := base64.RawURLEncoding.EncodeToString
+	document := map[string]any{"keys": []map[string]any{
+		{"kty": "oct", "kid": "hmac", "alg": "HS256", "k": encode(testHMACKey)},
+		{"kty": "RSA", "kid": "rsa", "use": "sig", "n": encode(testRSAKey.N.Bytes()), "e": encode(big.NewInt(int64(testRSAKey.E)).Bytes())},
+		{"kty": "EC", "kid": "ec", "crv": "P-256", "x": encode(testECKey.X.Bytes()), "y": encode(testECKey.Y.Bytes())},
+		{"kty": "RSA", "kid": "enc", "use": "enc"},
+	}}
+	data, _ := json.Marshal(document)
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// testVerifier returns a verifier for the test JWKS with a fixed clock.
+func testVerifier(t *testing.T) *JWTVerifier {
+	t.Helper()
+	testKeys(t)
+	keys, err := LoadJWKS(writeTestJWKS(t))
+	if err != nil {
+		t.Fatalf("LoadJWKS: %v", err)
+	}
+	verifier := NewJWTVerifier(keys, "api")
+	verifier.Issuer = "auth"
+	verifier.Clock = func() time.Time { return testNow }
+	return verifier
+}
+
+// validClaims returns claims accepted by testVerifier.
+func validClaims() map[string]any {
+	return map[string]any{
+		"sub": "alice",
+		"iss": "auth",
+		"aud": []string{"other", "api"},
+		"exp": testNow.Add(time.Hour).Unix(),
+		"nbf": testNow.Add(-time.Hour).Unix(),
+	}
+}
+
+// ======================
+// JWKS Tests
+// ======================
+
+func TestParseJWKS(t *testing.T) {
+	testKeys(t)
+	keys, err := LoadJWKS(writeTestJWKS(t))
+	if err != nil {
+		t.Fatalf("LoadJWKS: %v", err)
+	}
+	if len(keys.Keys) != 3 {
+		t.Fatalf("Expected encryption key skipped, got %d keys", len(keys.Keys))
+	}
+	if _, ok := keys.Keys[1].Key.(*rsa.PublicKey); !ok {
+		t.Errorf("Expected RSA public key, got %T", keys.Keys[1].Key)
+	}
+
+	invalid := []string{
+		`not json`,
+		`{"keys":[{"kty":"EC","crv":"P-384","x":"AA","y":"AA"}]}`,
+		`{"keys":[{"kty":"EC","crv":"P-256","x":"AQ","y":"AQ"}]}`,
+		`{"keys":[{"kty":"RSA","n":"AQAB","e":"AQ"}]}`,
+		`{"keys":[{"kty":"OKP"}]}`,
+	}
+	for _, document := range invalid {
+		if _, err := ParseJWKS([]byte(document)); err == nil {
+			t.Errorf("Expected error for %s", document)
+		}
+	}
+	if _, err := LoadJWKS(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("Expected error for missing file")
+	}
+}
+
+// ======================
+// JWT Verification Tests
+// ======================
+
+func TestJWTVerify(t *testing.T) {
+	verifier := testVerifier(t)
+
+	for _, algorithm := range []string{AlgorithmHS256, AlgorithmRS256, AlgorithmES256} {
+		t.Run(algorithm, func(t *testing.T) {
+			claims, err := verifier.Verify(signJWT(t, algorithm, "", validClaims()))
+			if err != nil {
+				t.Fatalf("Verify: %v", err)
+			}
+			if claims["sub"] != "alice" {
+				t.Errorf("Unexpected claims %v", claims)
+			}
+		})
+	}
+
+	with := func(name string, value any) map[string]any {
+		claims := validClaims()
+		if value == nil {
+			delete(claims, name)
+		} else {
+			claims[name] = value
+		}
+		return claims
+	}
+	tamper := func(token string) string {
+		return token[:len(token)-4] + "AAAA"
+	}
+	tests := []struct {
+		name     string
+		token    string
+		expected error
+	}{
+		{"expired", signJWT(t, AlgorithmHS256, "", with("exp", testNow.Add(-2*time.Minute).Unix())), ErrTokenExpired},
+		{"expired_within_leeway", signJWT(t, AlgorithmHS256, "", with("exp", testNow.Add(-30*time.Second).Unix())), nil},
+		{"not_yet_valid", signJWT(t, AlgorithmHS256, "", with("nbf", testNow.Add(2*time.Minute).Unix())), ErrTokenNotYetValid},
+		{"wrong_audience", signJWT(t, AlgorithmHS256, "", with("aud", "other")), ErrTokenAudience},
+		{"missing_audience", signJWT(t, AlgorithmHS256, "", with("aud", nil)), ErrTokenAudience},
+		{"string_audience", signJWT(t, AlgorithmHS256, "", with("aud", "api")), nil},
+		{"wrong_issuer", signJWT(t, AlgorithmHS256, "", with("iss", "evil")), ErrTokenIssuer},
+		{"non_numeric_exp", signJWT(t, AlgorithmHS256, "", with("exp", "tomorrow")), ErrTokenMalformed},
+		{"kid_selects_key", signJWT(t, AlgorithmRS256, "rsa", validClaims()), nil},
+		{"unknown_kid", signJWT(t, AlgorithmRS256, "missing", validClaims()), ErrTokenKeyNotFound},
+		{"kid_of_other_type", signJWT(t, AlgorithmHS256, "rsa", validClaims()), ErrTokenKeyNotFound},
+		{"tampered_signature", tamper(signJWT(t, AlgorithmES256, "", validClaims())), ErrTokenSignature},
+		{"none_algorithm", encodeSegment(map[string]any{"alg": "none"}) + "." + encodeSegment(validClaims()) + ".", ErrTokenAlgorithm},
+		{"two_segments", "a.b", ErrTokenMalformed},
+		{"bad_header", "!!.e30.", ErrTokenMalformed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := verifier.Verify(tt.token)
+			if tt.expected == nil {
+				if err != nil {
+					t.Fatalf("Expected valid token, got %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.expected) {
+				t.Fatalf("Expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestJWTVerifyAlgorithmRestriction(t *testing.T) {
+	verifier := testVerifier(t)
+	verifier.Algorithms = []string{AlgorithmES256}
+	if _, err := verifier.Verify(signJWT(t, AlgorithmHS256, "", validClaims())); !errors.Is(err, ErrTokenAlgorithm) {
+		t.Errorf("Expected ErrTokenAlgorithm, got %v", err)
+	}
+
+	// An HMAC key restricted to another algorithm must not be used
+	verifier = testVerifier(t)
+	verifier.Keys.Keys[0].Algorithm = AlgorithmRS256
+	if _, err := verifier.Verify(signJWT(t, AlgorithmHS256, "", validClaims())); !errors.Is(err, ErrTokenKeyNotFound) {
+		t.Errorf("Expected ErrTokenKeyNotFound, got %v", err)
+	}
+}
+
+func TestJWTAuth(t *testing.T) {
+	auth := NewJWTAuth("api", testVerifier(t))
+
+	request := httptest.NewRequest("GET", "/", nil)
+	request.Header.Set("Authorization", "Bearer "+signJWT(t, AlgorithmES256, "ec", validClaims()))
+	recorder, principal := serveAuth(auth, request)
+	if recorder.Code != http.StatusOK || principal == nil {
+		t.Fatalf("Expected authenticated request, got %d", recorder.Code)
+	}
+	if principal.Subject != "alice" || principal.Claims["iss"] != "auth" {
+		t.Errorf("Unexpected principal %+v", principal)
+	}
+
+	request = httptest.NewRequest("GET", "/", nil)
+	request.Header.Set("Authorization", "Bearer "+signJWT(t, AlgorithmES256, "ec", map[string]any{"sub": "alice"}))
+	recorder, _ = serveAuth(auth, request)
+	if recorder.Code != http.StatusUnauthorized || recorder.Header().Get("WWW-Authenticate") != `Bearer realm="api", error="invalid_token"` {
+		t.Errorf("Expected invalid_token response, got %d %q", recorder.Code, recorder.Header().Get("WWW-Authenticate"))
+	}
+}
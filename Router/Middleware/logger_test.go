@@ -0,0 +1,198 @@
+package Middleware
+
+import (
+	"LiteFrame/Router/Param"
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// ======================
+// Logger Middleware Tests
+// ======================
+
+// newTestLogger creates a Logger writing JSON records to buffer with a fixed clock
+func newTestLogger(buffer *bytes.Buffer) *Logger {
+	logger := NewLogger()
+	logger.Logger = slog.New(slog.NewJSONHandler(buffer, nil))
+	current := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+	logger.Clock = func() time.Time {
+		current = current.Add(25 * time.Millisecond)
+		return current
+	}
+	return logger
+}
+
+func serveLogged(logger *Logger, status int, body string, request *http.Request) {
+	handler := logger.GetHandler()(func(w http.ResponseWriter, r *http.Request, params *Param.Params) {
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	})
+	handler(httptest.NewRecorder(), request, nil)
+}
+
+func TestLogger(t *testing.T) {
+	t.Run("structured_record", func(t *testing.T) {
+		var buffer bytes.Buffer
+		logger := newTestLogger(&buffer)
+
+		request := httptest.NewRequest("GET", "/users/42?full=1", nil)
+		request.Pattern = "/users/:id"
+		request.RemoteAddr = "203.0.113.9:5123"
+		serveLogged(logger, http.StatusCreated, "hello", request)
+
+		var record map[string]any
+		if err := json.Unmarshal(buffer.Bytes(), &record); err != nil {
+			t.Fatalf("Failed to decode record %q: %v", buffer.String(), err)
+		}
+		expected := map[string]any{
+			"level":     "INFO",
+			"msg":       "request",
+			"method":    "GET",
+			"path":      "/users/42",
+			"route":     "/users/:id",
+			"status":    float64(201),
+			"bytes":     float64(5),
+			"latency":   float64(25 * time.Millisecond),
+			"client_ip": "203.0.113.9",
+		}
+		for key, value := range expected {
+			if record[key] != value {
+				t.Errorf("Field %s: expected %v, got %v", key, value, record[key])
+			}
+		}
+		if _, ok := record["user_agent"]; ok {
+			t.Error("Expected user_agent to be excluded by default")
+		}
+	})
+
+	t.Run("configurable_fields_and_levels", func(t *testing.T) {
+		var buffer bytes.Buffer
+		logger := newTestLogger(&buffer)
+		logger.Fields = LogStatus | LogUserAgent
+
+		request := httptest.NewRequest("GET", "/", nil)
+		request.Header.Set("User-Agent", "probe/1.0")
+		serveLogged(logger, http.StatusServiceUnavailable, "", request)
+
+		var record map[string]any
+		if err := json.Unmarshal(buffer.Bytes(), &record); err != nil {
+			t.Fatalf("Failed to decode record: %v", err)
+		}
+		if record["level"] != "ERROR" || record["user_agent"] != "probe/1.0" || record["status"] != float64(503) {
+			t.Errorf("Unexpected record: %v", record)
+		}
+		if _, ok := record["method"]; ok {
+			t.Error("Expected method to be excluded")
+		}
+	})
+
+	t.Run("sampling_keeps_errors", func(t *testing.T) {
+		var buffer bytes.Buffer
+		logger := newTestLogger(&buffer)
+		logger.SampleRate = 0.5
+		logger.Sample = func() float64 { return 0.9 }
+
+		serveLogged(logger, http.StatusOK, "", httptest.NewRequest("GET", "/", nil))
+		if buffer.Len() != 0 {
+			t.Errorf("Expected sampled-out request not to be logged, got %q", buffer.String())
+		}
+		serveLogged(logger, http.StatusNotFound, "", httptest.NewRequest("GET", "/", nil))
+		if !strings.Contains(buffer.String(), `"status":404`) {
+			t.Errorf("Expected error response to be logged, got %q", buffer.String())
+		}
+	})
+
+	t.Run("common_and_combined_format", func(t *testing.T) {
+		var buffer bytes.Buffer
+		logger := newTestLogger(&buffer)
+		logger.Output = &buffer
+		logger.Format = LogCommon
+
+		request := httptest.NewRequest("POST", "/items?x=1", nil)
+		request.RemoteAddr = "198.51.100.7:4000"
+		request.SetBasicAuth("alice", "secret")
+		serveLogged(logger, http.StatusOK, "done", request)
+
+		// Unverified credentials are not logged
+		expected := `198.51.100.7 - - [01/Mar/2024:12:00:00 +0000] "POST /items?x=1 HTTP/1.1" 200 4` + "\n"
+		if buffer.String() != expected {
+			t.Errorf("Unexpected common log line:\n%q\nexpected:\n%q", buffer.String(), expected)
+		}
+
+		buffer.Reset()
+		logger.Format = LogCombined
+		request = httptest.NewRequest("GET", "/", nil)
+		request.Header.Set("Referer", "https://example.com/")
+		request.Header.Set("User-Agent", "curl/8.0")
+		serveLogged(logger, http.StatusNoContent, "", request)
+		if !strings.HasSuffix(buffer.String(), `" 204 - "https://example.com/" "curl/8.0"`+"\n") {
+			t.Errorf("Unexpected combined log line: %q", buffer.String())
+		}
+	})
+}
+
+func TestLoggerAuthUser(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := newTestLogger(&buffer)
+	logger.Output = &buffer
+	logger.Format = LogCommon
+	auth := NewBasicAuth("test", func(ctx context.Context, username string, password string) (*Principal, error) {
+		if password != "secret" {
+			return nil, nil
+		}
+		return &Principal{Subject: username, Scheme: "Basic"}, nil
+	})
+	handler := logger.GetHandler()(auth.GetHandler()(func(w http.ResponseWriter, r *http.Request, params *Param.Params) {}))
+
+	tests := []struct {
+		name     string
+		username string
+		password string
+		user     string
+	}{
+		{"authenticated", "alice", "secret", "alice"},
+		{"forged_fields", "x\" 200 1\n1.2.3.4 - root", "secret", `x\x22\x20200\x201\x0A1.2.3.4\x20-\x20root`},
+		{"rejected", "mallory", "wrong", "-"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			buffer.Reset()
+			request := httptest.NewRequest("GET", "/", nil)
+			request.RemoteAddr = "198.51.100.7:4000"
+			request.SetBasicAuth(test.username, test.password)
+			handler(httptest.NewRecorder(), request, nil)
+
+			line := buffer.String()
+			if strings.Count(line, "\n") != 1 || !strings.HasPrefix(line, "198.51.100.7 - "+test.user+" [") {
+				t.Errorf("Expected authuser %q, got %q", test.user, line)
+			}
+		})
+	}
+}
+
+func TestResponseWriter(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	writer := NewResponseWriter(recorder)
+
+	if writer.Status != http.StatusOK || writer.WroteHeader {
+		t.Errorf("Unexpected initial state: %+v", writer)
+	}
+	writer.WriteHeader(http.StatusAccepted)
+	writer.WriteHeader(http.StatusTeapot)
+	_, _ = writer.Write([]byte("abc"))
+	_, _ = writer.Write([]byte("de"))
+
+	if writer.Status != http.StatusAccepted || writer.Bytes != 5 || !writer.WroteHeader {
+		t.Errorf("Unexpected recorded state: status=%d bytes=%d wrote=%v", writer.Status, writer.Bytes, writer.WroteHeader)
+	}
+	if writer.Unwrap() != recorder {
+		t.Error("Expected Unwrap to return the underlying writer")
+	}
+}
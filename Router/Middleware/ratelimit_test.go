@@ -0,0 +1,237 @@
+package Middleware
+
+import (
+	"LiteFrame/Router/Param"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// ======================
+// Rate Limit Tests
+// ======================
+
+func TestMemoryStoreTokenBucket(t *testing.T) {
+	store := NewMemoryStore(4)
+	limit := Limit{Requests: 2, Period: time.Second, Burst: 3}
+	now := time.Unix(1_700_000_000, 0)
+
+	for index := 0; index < 3; index++ {
+		decision, _ := store.Allow(context.Background(), "k", limit, now)
+		if !decision.Allowed || decision.Remaining != 2-index {
+			t.Fatalf("Request %d: expected allowed with %d remaining, got %+v", index, 2-index, decision)
+		}
+	}
+	decision, _ := store.Allow(context.Background(), "k", limit, now)
+	if decision.Allowed || decision.RetryAfter != 500*time.Millisecond {
+		t.Errorf("Expected rejection with 500ms retry, got %+v", decision)
+	}
+	if decision.Reset != 1500*time.Millisecond {
+		t.Errorf("Expected 1.5s until the bucket is full, got %v", decision.Reset)
+	}
+
+	decision, _ = store.Allow(context.Background(), "k", limit, now.Add(500*time.Millisecond))
+	if !decision.Allowed {
+		t.Errorf("Expected a refilled token after 500ms, got %+v", decision)
+	}
+	if decision, _ = store.Allow(context.Background(), "other", limit, now); !decision.Allowed {
+		t.Error("Expected keys to be limited independently")
+	}
+}
+
+func TestMemoryStoreTokenBucketExpiry(t *testing.T) {
+	store := NewMemoryStore(1)
+	// One token per second, ten seconds until a drained bucket is full again
+	limit := Limit{Requests: 1, Period: time.Second, Burst: 10}
+	now := time.Unix(1_700_000_000, 0)
+	for index := 0; index < 10; index++ {
+		_, _ = store.Allow(context.Background(), "k", limit, now)
+	}
+	decision, _ := store.Allow(context.Background(), "k", limit, now.Add(2*time.Second))
+	if !decision.Allowed || decision.Remaining != 1 {
+		t.Errorf("Expected the drained bucket to be kept and refilled by 2 tokens, got %+v", decision)
+	}
+}
+
+func TestMemoryStoreSlidingWindow(t *testing.T) {
+	store := NewMemoryStore(1)
+	limit := Limit{Requests: 4, Period: time.Minute, Algorithm: SlidingWindow}
+	start := time.Unix(1_700_000_000, 0)
+
+	for index := 0; index < 4; index++ {
+		if decision, _ := store.Allow(context.Background(), "k", limit, start); !decision.Allowed {
+			t.Fatalf("Request %d: expected allowed", index)
+		}
+	}
+	decision, _ := store.Allow(context.Background(), "k", limit, start.Add(30*time.Second))
+	if decision.Allowed || decision.Reset != 30*time.Second {
+		t.Errorf("Expected rejection within the window, got %+v", decision)
+	}
+
+	// Half-way into the next window the previous window counts 50%: 4*0.5 = 2 used
+	middle := start.Add(90 * time.Second)
+	for index := 0; index < 2; index++ {
+		if decision, _ := store.Allow(context.Background(), "k", limit, middle); !decision.Allowed {
+			t.Errorf("Request %d in next window: expected allowed, got %+v", index, decision)
+		}
+	}
+	decision, _ = store.Allow(context.Background(), "k", limit, middle)
+	if decision.Allowed || decision.RetryAfter != 15*time.Second {
+		t.Errorf("Expected rejection with 15s retry, got %+v", decision)
+	}
+
+	// Two windows later the history is gone
+	if decision, _ := store.Allow(context.Background(), "k", limit, start.Add(3*time.Minute)); !decision.Allowed || decision.Remaining != 3 {
+		t.Errorf("Expected fresh window, got %+v", decision)
+	}
+}
+
+func TestMemoryStoreEviction(t *testing.T) {
+	store := NewMemoryStore(1)
+	store.SweepInterval = time.Second
+	limit := Limit{Requests: 1, Period: time.Second}
+	now := time.Unix(1_700_000_000, 0)
+	for index := 0; index < 10; index++ {
+		_, _ = store.Allow(context.Background(), fmt.Sprint(index), limit, now)
+	}
+	if store.Len() != 10 {
+		t.Fatalf("Expected 10 entries, got %d", store.Len())
+	}
+	_, _ = store.Allow(context.Background(), "new", limit, now.Add(5*time.Second))
+	if store.Len() != 1 {
+		t.Errorf("Expected idle entries to be swept, got %d", store.Len())
+	}
+}
+
+// failingStore is a Store that always fails
+type failingStore struct{}
+
+func (failingStore) Allow(context.Context, string, Limit, time.Time) (Decision, error) {
+	return Decision{}, errors.New("backend down")
+}
+
+func TestRateLimit(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	serve := func(limiter *RateLimit, request *http.Request, params *Param.Params) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		limiter.GetHandler()(func(w http.ResponseWriter, r *http.Request, params *Param.Params) {
+			w.WriteHeader(http.StatusOK)
+		})(recorder, request, params)
+		return recorder
+	}
+	newLimiter := func() *RateLimit {
+		limiter := NewRateLimit(Limit{Requests: 2, Period: time.Minute})
+		limiter.Clock = func() time.Time { return now }
+		return limiter
+	}
+
+	t.Run("headers_and_429", func(t *testing.T) {
+		limiter := newLimiter()
+		request := httptest.NewRequest("GET", "/", nil)
+		recorder := serve(limiter, request, nil)
+		header := recorder.Header()
+		if recorder.Code != http.StatusOK || header.Get("RateLimit-Limit") != "2" || header.Get("RateLimit-Remaining") != "1" ||
+			header.Get("RateLimit-Reset") != "30" || header.Get("RateLimit-Policy") != "2;w=60" {
+			t.Errorf("Unexpected first response %d %v", recorder.Code, header)
+		}
+		serve(limiter, request, nil)
+		recorder = serve(limiter, request, nil)
+		if recorder.Code != http.StatusTooManyRequests || recorder.Header().Get("Retry-After") != "30" {
+			t.Errorf("Expected 429 with Retry-After 30, got %d %v", recorder.Code, recorder.Header())
+		}
+
+		other := httptest.NewRequest("GET", "/", nil)
+		other.RemoteAddr = "198.51.100.7:4000"
+		if serve(limiter, other, nil).Code != http.StatusOK {
+			t.Error("Expected another client IP to have its own quota")
+		}
+	})
+
+	t.Run("default_store_shared", func(t *testing.T) {
+		// serve builds the handler on every call, like a chain compiled per route
+		limiter := &RateLimit{Limit: Limit{Requests: 1, Period: time.Minute}}
+		request := httptest.NewRequest("GET", "/", nil)
+		if serve(limiter, request, nil).Code != http.StatusOK {
+			t.Fatal("Expected first request to pass")
+		}
+		if recorder := serve(limiter, request, nil); recorder.Code != http.StatusTooManyRequests {
+			t.Errorf("Expected the default store to be shared between handlers, got %d", recorder.Code)
+		}
+	})
+
+	t.Run("key_extractors", func(t *testing.T) {
+		params := Param.NewParams()
+		params.Path = "/tenants/acme"
+		params.Add("tenant", 9, 13)
+		request := httptest.NewRequest("GET", "/tenants/acme", nil)
+		request.Pattern = "/tenants/:tenant"
+		request.Header.Set("X-API-Key", "secret")
+
+		tests := map[string]struct {
+			key      KeyFunc
+			expected string
+		}{
+			"ip":       {KeyByIP, "192.0.2.1"},
+			"route":    {KeyByRoute, "/tenants/:tenant"},
+			"header":   {KeyByHeader("X-API-Key"), "secret"},
+			"param":    {KeyByParam("tenant"), "acme"},
+			"combined": {CombineKeys(KeyByRoute, KeyByParam("tenant")), "/tenants/:tenant|acme"},
+			"missing":  {CombineKeys(KeyByIP, KeyByHeader("X-Other")), ""},
+		}
+		for name, test := range tests {
+			if got := test.key(request, params); got != test.expected {
+				t.Errorf("%s: expected %q, got %q", name, test.expected, got)
+			}
+		}
+		if KeyByParam("tenant")(request, nil) != "" {
+			t.Error("Expected empty key for nil params")
+		}
+	})
+
+	t.Run("empty_key_exempt", func(t *testing.T) {
+		limiter := newLimiter()
+		limiter.Key = KeyByHeader("X-API-Key")
+		for index := 0; index < 5; index++ {
+			if recorder := serve(limiter, httptest.NewRequest("GET", "/", nil), nil); recorder.Code != http.StatusOK || recorder.Header().Get("RateLimit-Limit") != "" {
+				t.Fatalf("Expected unkeyed request to pass without headers, got %d", recorder.Code)
+			}
+		}
+	})
+
+	t.Run("route_override", func(t *testing.T) {
+		limiter := newLimiter()
+		limiter.Routes = map[string]Limit{"/login": {Requests: 1, Period: time.Minute}, "/health": {}}
+		login := httptest.NewRequest("POST", "/login", nil)
+		login.Pattern = "/login"
+		serve(limiter, login, nil)
+		if serve(limiter, login, nil).Code != http.StatusTooManyRequests {
+			t.Error("Expected /login override to allow a single request")
+		}
+		if serve(limiter, httptest.NewRequest("GET", "/", nil), nil).Code != http.StatusOK {
+			t.Error("Expected default policy to be counted separately")
+		}
+		health := httptest.NewRequest("GET", "/health", nil)
+		health.Pattern = "/health"
+		for index := 0; index < 5; index++ {
+			if serve(limiter, health, nil).Code != http.StatusOK {
+				t.Fatal("Expected zero policy to disable limiting")
+			}
+		}
+	})
+
+	t.Run("store_failure", func(t *testing.T) {
+		limiter := newLimiter()
+		limiter.Store = failingStore{}
+		if code := serve(limiter, httptest.NewRequest("GET", "/", nil), nil).Code; code != http.StatusInternalServerError {
+			t.Errorf("Expected 500 on store failure, got %d", code)
+		}
+		limiter.FailOpen = true
+		if code := serve(limiter, httptest.NewRequest("GET", "/", nil), nil).Code; code != http.StatusOK {
+			t.Errorf("Expected fail-open to allow the request, got %d", code)
+		}
+	})
+}
@@ -0,0 +1,159 @@
+package Middleware
+
+import (
+	"LiteFrame/Router/Param"
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// ======================
+// Real IP Tests
+// ======================
+
+func TestParsePrefixes(t *testing.T) {
+	prefixes, err := ParsePrefixes([]string{"10.1.2.3/8", " 192.0.2.7 ", "2001:db8::/32", "::ffff:203.0.113.0/120"})
+	if err != nil {
+		t.Fatalf("ParsePrefixes: %v", err)
+	}
+	expected := []string{"10.0.0.0/8", "192.0.2.7/32", "2001:db8::/32", "203.0.113.0/24"}
+	for index, prefix := range prefixes {
+		if prefix.String() != expected[index] {
+			t.Errorf("Prefix %d: expected %s, got %s", index, expected[index], prefix)
+		}
+	}
+	for _, invalid := range []string{"10.0.0.0/33", "example.com", ""} {
+		if _, err := ParsePrefixes([]string{invalid}); err == nil {
+			t.Errorf("Expected error for %q", invalid)
+		}
+	}
+}
+
+func TestRealIPResolve(t *testing.T) {
+	realIP, err := NewRealIP(HeaderForwarded, "10.0.0.0/8", "2001:db8::/32")
+	if err != nil {
+		t.Fatalf("NewRealIP: %v", err)
+	}
+	realIP.Headers = append(realIP.Headers, HeaderXForwardedFor, HeaderXRealIP)
+
+	tests := []struct {
+		name           string
+		remote         string
+		headers        map[string][]string
+		expectedIP     string
+		expectedScheme string
+	}{
+		{"untrusted_peer_ignores_headers", "203.0.113.5:1000", map[string][]string{"X-Forwarded-For": {"198.51.100.1"}}, "203.0.113.5", "http"},
+		{"no_headers", "10.0.0.1:1000", nil, "10.0.0.1", "http"},
+		{"xff_single", "10.0.0.1:1000", map[string][]string{"X-Forwarded-For": {"198.51.100.1"}}, "198.51.100.1", "http"},
+		{"xff_skips_trusted_hops", "10.0.0.1:1000", map[string][]string{"X-Forwarded-For": {"198.51.100.1, 10.0.0.9"}}, "198.51.100.1", "http"},
+		{"xff_spoofed_prefix", "10.0.0.1:1000", map[string][]string{"X-Forwarded-For": {"1.1.1.1, 198.51.100.1"}}, "198.51.100.1", "http"},
+		{"xff_multiple_headers", "10.0.0.1:1000", map[string][]string{"X-Forwarded-For": {"198.51.100.1", "10.0.0.9"}}, "198.51.100.1", "http"},
+		{"xff_all_trusted", "10.0.0.1:1000", map[string][]string{"X-Forwarded-For": {"10.0.0.7, 10.0.0.8"}}, "10.0.0.7", "http"},
+		{"xff_garbage_stops_walk", "10.0.0.1:1000", map[string][]string{"X-Forwarded-For": {"198.51.100.1, garbage, 10.0.0.9"}}, "10.0.0.9", "http"},
+		{"xff_with_proto", "10.0.0.1:1000", map[string][]string{"X-Forwarded-For": {"198.51.100.1"}, "X-Forwarded-Proto": {"https"}}, "198.51.100.1", "https"},
+		{"xff_paired_proto", "10.0.0.1:1000", map[string][]string{"X-Forwarded-For": {"198.51.100.1, 10.0.0.9"}, "X-Forwarded-Proto": {"https, http"}}, "198.51.100.1", "https"},
+		{"xff_ipv6", "[2001:db8::1]:1000", map[string][]string{"X-Forwarded-For": {"2001:db8:ffff::1, 2001:db8::2"}}, "2001:db8:ffff::1", "http"},
+		{"forwarded", "10.0.0.1:1000", map[string][]string{"Forwarded": {`for=198.51.100.1;proto=https, for="10.0.0.9:8080"`}}, "198.51.100.1", "https"},
+		{"forwarded_ipv6", "10.0.0.1:1000", map[string][]string{"Forwarded": {`For="[2001:db8:cafe::17]:4711";Proto=https`}}, "2001:db8:cafe::17", "https"},
+		{"forwarded_obfuscated", "10.0.0.1:1000", map[string][]string{"Forwarded": {`for=_hidden, for=10.0.0.9;proto=https`}}, "10.0.0.9", "https"},
+		{"forwarded_wins", "10.0.0.1:1000", map[string][]string{"Forwarded": {"for=198.51.100.1"}, "X-Forwarded-For": {"198.51.100.2"}}, "198.51.100.1", "http"},
+		{"forwarded_quoted_separator", "10.0.0.1:1000", map[string][]string{"Forwarded": {`for=198.51.100.1;host="a,b;c"`}}, "198.51.100.1", "http"},
+		{"real_ip", "10.0.0.1:1000", map[string][]string{"X-Real-IP": {"198.51.100.3"}}, "198.51.100.3", "http"},
+		{"real_ip_invalid", "10.0.0.1:1000", map[string][]string{"X-Real-IP": {"nope"}}, "10.0.0.1", "http"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest("GET", "/", nil)
+			request.RemoteAddr = tt.remote
+			for name, values := range tt.headers {
+				for _, value := range values {
+					request.Header.Add(name, value)
+				}
+			}
+			ip, scheme := realIP.Resolve(request)
+			if ip != tt.expectedIP || scheme != tt.expectedScheme {
+				t.Errorf("Expected %s %s, got %s %s", tt.expectedIP, tt.expectedScheme, ip, scheme)
+			}
+		})
+	}
+}
+
+func TestRealIPHeaderOrder(t *testing.T) {
+	realIP, _ := NewRealIP("x-real-ip", "10.0.0.0/8")
+	request := httptest.NewRequest("GET", "/", nil)
+	request.RemoteAddr = "10.0.0.1:1000"
+	request.Header.Set("X-Forwarded-For", "198.51.100.1")
+	request.Header.Set("X-Real-IP", "198.51.100.3")
+	if ip, _ := realIP.Resolve(request); ip != "198.51.100.3" {
+		t.Errorf("Expected only X-Real-IP to be consulted, got %s", ip)
+	}
+}
+
+func TestRealIPSingleHeader(t *testing.T) {
+	realIP, _ := NewRealIP(HeaderXForwardedFor, "10.0.0.0/8")
+	request := httptest.NewRequest("GET", "/", nil)
+	request.RemoteAddr = "10.0.0.1:1000"
+	// The proxy appends to X-Forwarded-For but passes the client's Forwarded header through
+	request.Header.Set("Forwarded", "for=1.1.1.1;proto=https")
+	request.Header.Set("X-Real-IP", "1.1.1.2")
+	request.Header.Set("X-Forwarded-For", "198.51.100.1")
+	if ip, scheme := realIP.Resolve(request); ip != "198.51.100.1" || scheme != "http" {
+		t.Errorf("Expected client-supplied headers to be ignored, got %s %s", ip, scheme)
+	}
+
+	none, _ := NewRealIP("", "10.0.0.0/8")
+	if none.Headers != nil {
+		t.Errorf("Expected no headers, got %v", none.Headers)
+	}
+	if ip, _ := none.Resolve(request); ip != "10.0.0.1" {
+		t.Errorf("Expected RemoteAddr without headers, got %s", ip)
+	}
+	if _, err := NewRealIP(HeaderXForwardedFor, "10.0.0.0/99"); err == nil {
+		t.Error("Expected error for invalid proxy")
+	}
+}
+
+func TestRealIPContext(t *testing.T) {
+	realIP, _ := NewRealIP(HeaderXForwardedFor, "10.0.0.0/8")
+
+	request := httptest.NewRequest("GET", "/", nil)
+	request.RemoteAddr = "10.0.0.1:1000"
+	request.TLS = &tls.ConnectionState{}
+	if ClientIP(request) != "10.0.0.1" || RequestScheme(request) != "https" {
+		t.Errorf("Expected connection values without RealIP, got %s %s", ClientIP(request), RequestScheme(request))
+	}
+
+	request.Header.Set("X-Forwarded-For", "198.51.100.1")
+	request.Header.Set("X-Forwarded-Proto", "http")
+	var ip, scheme string
+	realIP.GetHandler()(func(w http.ResponseWriter, r *http.Request, params *Param.Params) {
+		ip, scheme = ClientIP(r), RequestScheme(r)
+	})(httptest.NewRecorder(), request, nil)
+	if ip != "198.51.100.1" || scheme != "http" {
+		t.Errorf("Expected resolved values in context, got %s %s", ip, scheme)
+	}
+}
+
+func TestRealIPLogger(t *testing.T) {
+	var buffer bytes.Buffer
+	realIP, _ := NewRealIP(HeaderXForwardedFor, "10.0.0.0/8")
+	request := httptest.NewRequest("GET", "/", nil)
+	request.RemoteAddr = "10.0.0.1:1000"
+	request.Header.Set("X-Forwarded-For", "198.51.100.1")
+
+	handler := func(w http.ResponseWriter, r *http.Request, params *Param.Params) {}
+	handler = newTestLogger(&buffer).GetHandler()(handler)
+	realIP.GetHandler()(handler)(httptest.NewRecorder(), request, nil)
+
+	var record map[string]any
+	if err := json.Unmarshal(buffer.Bytes(), &record); err != nil {
+		t.Fatalf("Failed to decode record %q: %v", buffer.String(), err)
+	}
+	if record["client_ip"] != "198.51.100.1" {
+		t.Errorf("Expected resolved client_ip, got %v", record["client_ip"])
+	}
+}
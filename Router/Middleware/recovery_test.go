@@ -0,0 +1,131 @@
+package Middleware
+
+import (
+	"LiteFrame/Router/Param"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// ======================
+// Recovery Middleware Tests
+// ======================
+
+func TestRecovery(t *testing.T) {
+	panicking := func(w http.ResponseWriter, r *http.Request, params *Param.Params) {
+		panic("boom")
+	}
+
+	t.Run("responds_500_and_calls_hook", func(t *testing.T) {
+		var info PanicInfo
+		recovery := NewRecovery()
+		recovery.Hook = func(received PanicInfo) { info = received }
+
+		request := httptest.NewRequest("GET", "/users/1", nil)
+		request.Pattern = "/users/:id"
+		recorder := httptest.NewRecorder()
+		recovery.GetHandler()(panicking)(recorder, request, nil)
+
+		if recorder.Code != http.StatusInternalServerError {
+			t.Errorf("Expected status 500, got %d", recorder.Code)
+		}
+		if strings.Contains(recorder.Body.String(), "boom") {
+			t.Error("Expected panic value not to be exposed in response")
+		}
+		if info.Value != "boom" || info.Pattern != "/users/:id" || info.Request != request {
+			t.Errorf("Unexpected panic info: %+v", info)
+		}
+		if !strings.Contains(string(info.Stack), "recovery_test.go") {
+			t.Error("Expected stack trace to include the panicking function")
+		}
+	})
+
+	t.Run("custom_error_handler_receives_panic_error", func(t *testing.T) {
+		cause := errors.New("nil map write")
+		var received error
+		recovery := &Recovery{ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			received = err
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}}
+
+		recorder := httptest.NewRecorder()
+		recovery.GetHandler()(func(w http.ResponseWriter, r *http.Request, params *Param.Params) {
+			panic(cause)
+		})(recorder, httptest.NewRequest("GET", "/", nil), nil)
+
+		var panicErr *PanicError
+		if !errors.As(received, &panicErr) || !errors.Is(received, cause) {
+			t.Errorf("Expected PanicError wrapping cause, got %v", received)
+		}
+		if recorder.Code != http.StatusServiceUnavailable {
+			t.Errorf("Expected status 503, got %d", recorder.Code)
+		}
+	})
+
+	t.Run("abort_handler_is_repanicked", func(t *testing.T) {
+		defer func() {
+			if recovered := recover(); recovered != http.ErrAbortHandler {
+				t.Errorf("Expected http.ErrAbortHandler, got %v", recovered)
+			}
+		}()
+		NewRecovery().GetHandler()(func(w http.ResponseWriter, r *http.Request, params *Param.Params) {
+			panic(http.ErrAbortHandler)
+		})(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil), nil)
+	})
+
+	t.Run("no_panic_passthrough", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		NewRecovery().GetHandler()(func(w http.ResponseWriter, r *http.Request, params *Param.Params) {
+			w.WriteHeader(http.StatusNoContent)
+		})(recorder, httptest.NewRequest("GET", "/", nil), nil)
+		if recorder.Code != http.StatusNoContent {
+			t.Errorf("Expected status 204, got %d", recorder.Code)
+		}
+	})
+
+	t.Run("falls_back_to_context_error_handler", func(t *testing.T) {
+		var received error
+		request := httptest.NewRequest("GET", "/", nil)
+		request = request.WithContext(WithErrorHandler(request.Context(), func(w http.ResponseWriter, r *http.Request, err error) {
+			received = err
+			w.WriteHeader(http.StatusTeapot)
+		}))
+		recorder := httptest.NewRecorder()
+		(&Recovery{}).GetHandler()(panicking)(recorder, request, nil)
+
+		var panicErr *PanicError
+		if !errors.As(received, &panicErr) || recorder.Code != http.StatusTeapot {
+			t.Errorf("Expected the context ErrorHandler to receive the PanicError, got %v (%d)", received, recorder.Code)
+		}
+	})
+
+	t.Run("skip_response", func(t *testing.T) {
+		hooked := false
+		recovery := &Recovery{Hook: func(PanicInfo) { hooked = true }, SkipResponse: true}
+		recorder := httptest.NewRecorder()
+		recovery.GetHandler()(panicking)(recorder, httptest.NewRequest("GET", "/", nil), nil)
+		if !hooked || recorder.Body.Len() != 0 || recorder.Result().Header.Get("Content-Type") != "" {
+			t.Errorf("Expected only the hook to run, got body %q", recorder.Body.String())
+		}
+	})
+
+	t.Run("header_already_written", func(t *testing.T) {
+		called := false
+		recovery := &Recovery{ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			called = true
+		}}
+		recorder := httptest.NewRecorder()
+		recovery.GetHandler()(func(w http.ResponseWriter, r *http.Request, params *Param.Params) {
+			w.WriteHeader(http.StatusAccepted)
+			panic("late")
+		})(recorder, httptest.NewRequest("GET", "/", nil), nil)
+		if called {
+			t.Error("Expected ErrorHandler to be skipped after the header was sent")
+		}
+		if recorder.Code != http.StatusAccepted {
+			t.Errorf("Expected status 202, got %d", recorder.Code)
+		}
+	})
+}
@@ -0,0 +1,108 @@
+package Middleware
+
+import (
+	"LiteFrame/Router/Param"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// ======================
+// RequestID Middleware Tests
+// ======================
+
+func TestRequestID(t *testing.T) {
+	serve := func(middleware *RequestID, request *http.Request) (string, *httptest.ResponseRecorder) {
+		seen := ""
+		recorder := httptest.NewRecorder()
+		middleware.GetHandler()(func(w http.ResponseWriter, r *http.Request, params *Param.Params) {
+			seen = GetRequestID(r.Context())
+		})(recorder, request, nil)
+		return seen, recorder
+	}
+
+	t.Run("generated", func(t *testing.T) {
+		seen, recorder := serve(NewRequestID(), httptest.NewRequest("GET", "/", nil))
+		if len(seen) != 26 {
+			t.Errorf("Expected 26 character generated ID, got %q", seen)
+		}
+		if recorder.Header().Get(DefaultRequestIDHeader) != seen {
+			t.Errorf("Expected response header %q, got %q", seen, recorder.Header().Get(DefaultRequestIDHeader))
+		}
+	})
+
+	t.Run("incoming_reused", func(t *testing.T) {
+		request := httptest.NewRequest("GET", "/", nil)
+		request.Header.Set(DefaultRequestIDHeader, "upstream-123")
+		if seen, _ := serve(NewRequestID(), request); seen != "upstream-123" {
+			t.Errorf("Expected incoming ID to be reused, got %q", seen)
+		}
+	})
+
+	t.Run("incoming_rejected", func(t *testing.T) {
+		for _, incoming := range []string{"has space", "line\nbreak", strings.Repeat("a", MaxRequestIDLength+1)} {
+			request := httptest.NewRequest("GET", "/", nil)
+			request.Header.Set(DefaultRequestIDHeader, incoming)
+			if seen, _ := serve(NewRequestID(), request); seen == incoming || len(seen) != 26 {
+				t.Errorf("Expected %q to be replaced by a generated ID, got %q", incoming, seen)
+			}
+		}
+	})
+
+	t.Run("custom_header_untrusted", func(t *testing.T) {
+		middleware := &RequestID{Header: "X-Trace", Generator: func() string { return "fixed" }}
+		request := httptest.NewRequest("GET", "/", nil)
+		request.Header.Set("X-Trace", "client-chosen")
+		seen, recorder := serve(middleware, request)
+		if seen != "fixed" || recorder.Header().Get("X-Trace") != "fixed" {
+			t.Errorf("Expected generated ID in context and X-Trace, got %q / %q", seen, recorder.Header().Get("X-Trace"))
+		}
+	})
+}
+
+func TestNewRequestIDStringSortable(t *testing.T) {
+	ids := make([]string, 1000)
+	for index := range ids {
+		ids[index] = NewRequestIDString()
+	}
+	if !sort.StringsAreSorted(ids) {
+		t.Error("Expected IDs to sort by creation order")
+	}
+	unique := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		unique[id] = struct{}{}
+	}
+	if len(unique) != len(ids) {
+		t.Errorf("Expected %d unique IDs, got %d", len(ids), len(unique))
+	}
+}
+
+func TestRequestIDPropagation(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := newTestLogger(&buffer)
+	var info PanicInfo
+	recovery := &Recovery{Hook: func(panicInfo PanicInfo) { info = panicInfo }}
+
+	handler := NewRequestID().GetHandler()(logger.GetHandler()(recovery.GetHandler()(
+		func(w http.ResponseWriter, r *http.Request, params *Param.Params) {
+			panic("boom")
+		})))
+	request := httptest.NewRequest("GET", "/", nil)
+	request.Header.Set(DefaultRequestIDHeader, "req-42")
+	handler(httptest.NewRecorder(), request, nil)
+
+	if info.RequestID != "req-42" {
+		t.Errorf("Expected PanicInfo.RequestID req-42, got %q", info.RequestID)
+	}
+	var record map[string]any
+	if err := json.Unmarshal(buffer.Bytes(), &record); err != nil {
+		t.Fatalf("Failed to decode record %q: %v", buffer.String(), err)
+	}
+	if record["request_id"] != "req-42" {
+		t.Errorf("Expected request_id req-42 in log record, got %v", record["request_id"])
+	}
+}
@@ -0,0 +1,215 @@
+package Middleware
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// ======================
+// ResponseWriter Wrapper Tests
+// ======================
+
+// fullWriter implements every optional interface WrapResponseWriter knows about.
+type fullWriter struct {
+	*httptest.ResponseRecorder
+	flushed  bool
+	hijacked bool
+	readFrom bool
+	pushed   string
+	deadline bool
+}
+
+func (instance *fullWriter) Flush() {
+	instance.flushed = true
+	instance.ResponseRecorder.Flush()
+}
+
+func (instance *fullWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	instance.hijacked = true
+	return nil, nil, nil
+}
+
+func (instance *fullWriter) ReadFrom(source io.Reader) (int64, error) {
+	instance.readFrom = true
+	return io.Copy(instance.ResponseRecorder, source)
+}
+
+func (instance *fullWriter) SetWriteDeadline(time.Time) error {
+	instance.deadline = true
+	return nil
+}
+
+func (instance *fullWriter) Push(target string, _ *http.PushOptions) error {
+	instance.pushed = target
+	return nil
+}
+
+// writerWithMask exposes only the optional interfaces selected by mask.
+func writerWithMask(full *fullWriter, mask int) http.ResponseWriter {
+	type rw = http.ResponseWriter
+	switch mask {
+	case 0:
+		return struct{ rw }{full}
+	case supportsFlusher:
+		return struct {
+			rw
+			http.Flusher
+		}{full, full}
+	case supportsHijacker:
+		return struct {
+			rw
+			http.Hijacker
+		}{full, full}
+	case supportsReaderFrom:
+		return struct {
+			rw
+			io.ReaderFrom
+		}{full, full}
+	case supportsPusher:
+		return struct {
+			rw
+			http.Pusher
+		}{full, full}
+	case supportsFlusher | supportsHijacker:
+		return struct {
+			rw
+			http.Flusher
+			http.Hijacker
+		}{full, full, full}
+	case supportsFlusher | supportsReaderFrom:
+		return struct {
+			rw
+			http.Flusher
+			io.ReaderFrom
+		}{full, full, full}
+	case supportsFlusher | supportsPusher:
+		return struct {
+			rw
+			http.Flusher
+			http.Pusher
+		}{full, full, full}
+	case supportsHijacker | supportsReaderFrom:
+		return struct {
+			rw
+			http.Hijacker
+			io.ReaderFrom
+		}{full, full, full}
+	case supportsHijacker | supportsPusher:
+		return struct {
+			rw
+			http.Hijacker
+			http.Pusher
+		}{full, full, full}
+	case supportsReaderFrom | supportsPusher:
+		return struct {
+			rw
+			io.ReaderFrom
+			http.Pusher
+		}{full, full, full}
+	case supportsFlusher | supportsHijacker | supportsReaderFrom:
+		return struct {
+			rw
+			http.Flusher
+			http.Hijacker
+			io.ReaderFrom
+		}{full, full, full, full}
+	case supportsFlusher | supportsHijacker | supportsPusher:
+		return struct {
+			rw
+			http.Flusher
+			http.Hijacker
+			http.Pusher
+		}{full, full, full, full}
+	case supportsFlusher | supportsReaderFrom | supportsPusher:
+		return struct {
+			rw
+			http.Flusher
+			io.ReaderFrom
+			http.Pusher
+		}{full, full, full, full}
+	case supportsHijacker | supportsReaderFrom | supportsPusher:
+		return struct {
+			rw
+			http.Hijacker
+			io.ReaderFrom
+			http.Pusher
+		}{full, full, full, full}
+	default:
+		return full
+	}
+}
+
+func TestWrapResponseWriterInterfaces(t *testing.T) {
+	for mask := 0; mask < 16; mask++ {
+		full := &fullWriter{ResponseRecorder: httptest.NewRecorder()}
+		recorder, wrapped := WrapResponseWriter(writerWithMask(full, mask))
+
+		flusher, isFlusher := wrapped.(http.Flusher)
+		hijacker, isHijacker := wrapped.(http.Hijacker)
+		readerFrom, isReaderFrom := wrapped.(io.ReaderFrom)
+		pusher, isPusher := wrapped.(http.Pusher)
+		if isFlusher != (mask&supportsFlusher != 0) || isHijacker != (mask&supportsHijacker != 0) ||
+			isReaderFrom != (mask&supportsReaderFrom != 0) || isPusher != (mask&supportsPusher != 0) {
+			t.Errorf("mask %04b: unexpected interfaces flusher=%v hijacker=%v readerFrom=%v pusher=%v",
+				mask, isFlusher, isHijacker, isReaderFrom, isPusher)
+			continue
+		}
+		if _, ok := wrapped.(Recorder); !ok {
+			t.Errorf("mask %04b: expected wrapped writer to implement Recorder", mask)
+		}
+
+		if isReaderFrom {
+			written, err := readerFrom.ReadFrom(strings.NewReader("hello"))
+			if err != nil || written != 5 || !full.readFrom || recorder.Bytes != 5 {
+				t.Errorf("mask %04b: ReadFrom not forwarded or counted (bytes=%d)", mask, recorder.Bytes)
+			}
+		}
+		if isFlusher {
+			flusher.Flush()
+			if !full.flushed || !recorder.WroteHeader {
+				t.Errorf("mask %04b: Flush not forwarded or recorded", mask)
+			}
+		}
+		if isPusher {
+			if err := pusher.Push("/style.css", nil); err != nil || full.pushed != "/style.css" {
+				t.Errorf("mask %04b: Push not forwarded", mask)
+			}
+		}
+		if isHijacker {
+			if _, _, err := hijacker.Hijack(); err != nil || !full.hijacked || !recorder.Hijacked {
+				t.Errorf("mask %04b: Hijack not forwarded or recorded", mask)
+			}
+		}
+	}
+}
+
+func TestWrapResponseWriterController(t *testing.T) {
+	full := &fullWriter{ResponseRecorder: httptest.NewRecorder()}
+	recorder, wrapped := WrapResponseWriter(full)
+
+	// SetWriteDeadline is not promoted by the wrapper; ResponseController reaches it through Unwrap
+	if err := http.NewResponseController(wrapped).SetWriteDeadline(time.Now()); err != nil || !full.deadline {
+		t.Fatalf("Expected ResponseController to reach the underlying writer, got %v", err)
+	}
+	if recorder.Unwrap() != full {
+		t.Error("Expected Unwrap to return the underlying writer")
+	}
+}
+
+func TestWrapResponseWriterNested(t *testing.T) {
+	outer, wrapped := WrapResponseWriter(httptest.NewRecorder())
+	inner, rewrapped := WrapResponseWriter(wrapped)
+	if inner != outer {
+		t.Error("Expected nested wrapping to reuse the existing recorder")
+	}
+	rewrapped.WriteHeader(http.StatusCreated)
+	if outer.Status != http.StatusCreated {
+		t.Errorf("Expected shared status 201, got %d", outer.Status)
+	}
+}
@@ -0,0 +1,94 @@
+package Middleware
+
+import (
+	"LiteFrame/Router/Param"
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// ======================
+// Security Headers Tests
+// ======================
+
+func TestSecurityHeaders(t *testing.T) {
+	serve := func(headers *SecurityHeaders, request *http.Request) (*httptest.ResponseRecorder, string) {
+		nonce := ""
+		recorder := httptest.NewRecorder()
+		headers.GetHandler()(func(w http.ResponseWriter, r *http.Request, params *Param.Params) {
+			nonce = GetCSPNonce(r.Context())
+		})(recorder, request, nil)
+		return recorder, nonce
+	}
+
+	t.Run("html_defaults", func(t *testing.T) {
+		request := httptest.NewRequest("GET", "https://example.com/", nil)
+		request.TLS = &tls.ConnectionState{}
+		recorder, nonce := serve(NewSecurityHeaders(), request)
+		header := recorder.Header()
+		expected := map[string]string{
+			"Strict-Transport-Security": "max-age=31536000; includeSubDomains",
+			"X-Content-Type-Options":    "nosniff",
+			"X-Frame-Options":           "DENY",
+			"Referrer-Policy":           "strict-origin-when-cross-origin",
+			"Permissions-Policy":        "camera=(), microphone=(), geolocation=()",
+		}
+		for name, value := range expected {
+			if header.Get(name) != value {
+				t.Errorf("%s: expected %q, got %q", name, value, header.Get(name))
+			}
+		}
+		if len(nonce) != 24 {
+			t.Fatalf("Expected base64 128-bit nonce in context, got %q", nonce)
+		}
+		csp := header.Get("Content-Security-Policy")
+		if strings.Contains(csp, CSPNoncePlaceholder) || strings.Count(csp, "'nonce-"+nonce+"'") != 2 {
+			t.Errorf("Expected nonce substituted into CSP, got %q", csp)
+		}
+	})
+
+	t.Run("nonce_per_request", func(t *testing.T) {
+		headers := NewSecurityHeaders()
+		_, first := serve(headers, httptest.NewRequest("GET", "/", nil))
+		_, second := serve(headers, httptest.NewRequest("GET", "/", nil))
+		if first == second {
+			t.Error("Expected a fresh nonce for every request")
+		}
+	})
+
+	t.Run("hsts_only_over_https", func(t *testing.T) {
+		recorder, _ := serve(NewSecurityHeaders(), httptest.NewRequest("GET", "/", nil))
+		if recorder.Header().Get("Strict-Transport-Security") != "" {
+			t.Error("Expected no HSTS over plain HTTP")
+		}
+	})
+
+	t.Run("api_preset", func(t *testing.T) {
+		recorder, nonce := serve(NewAPISecurityHeaders(), httptest.NewRequest("GET", "/", nil))
+		if nonce != "" {
+			t.Errorf("Expected no nonce without placeholder, got %q", nonce)
+		}
+		if recorder.Header().Get("Content-Security-Policy") != "default-src 'none'; frame-ancestors 'none'" ||
+			recorder.Header().Get("Referrer-Policy") != "no-referrer" {
+			t.Errorf("Unexpected API headers %v", recorder.Header())
+		}
+		if _, ok := recorder.Header()["Permissions-Policy"]; ok {
+			t.Error("Expected empty fields to be omitted")
+		}
+	})
+
+	t.Run("report_only_and_preload", func(t *testing.T) {
+		headers := &SecurityHeaders{HSTSMaxAge: 600e9, HSTSPreload: true, ContentSecurityPolicy: "default-src 'self'", CSPReportOnly: true}
+		request := httptest.NewRequest("GET", "/", nil)
+		request.TLS = &tls.ConnectionState{}
+		recorder, _ := serve(headers, request)
+		if recorder.Header().Get("Content-Security-Policy-Report-Only") != "default-src 'self'" || recorder.Header().Get("Content-Security-Policy") != "" {
+			t.Errorf("Expected report-only CSP, got %v", recorder.Header())
+		}
+		if recorder.Header().Get("Strict-Transport-Security") != "max-age=600; preload" {
+			t.Errorf("Unexpected HSTS %q", recorder.Header().Get("Strict-Transport-Security"))
+		}
+	})
+}
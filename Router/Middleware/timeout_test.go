@@ -0,0 +1,114 @@
+package Middleware
+
+import (
+	"LiteFrame/Router/Error"
+	"LiteFrame/Router/Param"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// ======================
+// Timeout Middleware Tests
+// ======================
+
+func TestTimeout(t *testing.T) {
+	t.Run("fast_handler", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		NewTimeout(time.Second).GetHandler()(func(w http.ResponseWriter, r *http.Request, params *Param.Params) {
+			if _, ok := r.Context().Deadline(); !ok {
+				t.Error("Expected request context to carry a deadline")
+			}
+			w.Header().Set("X-Handler", "yes")
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte("done"))
+		})(recorder, httptest.NewRequest("GET", "/", nil), nil)
+		if recorder.Code != http.StatusCreated || recorder.Body.String() != "done" || recorder.Header().Get("X-Handler") != "yes" {
+			t.Errorf("Expected buffered response to be copied, got %d %q %v", recorder.Code, recorder.Body.String(), recorder.Header())
+		}
+	})
+
+	t.Run("informational_status_ignored", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		NewTimeout(time.Second).GetHandler()(func(w http.ResponseWriter, r *http.Request, params *Param.Params) {
+			w.Header().Set("Link", "</app.css>; rel=preload")
+			w.WriteHeader(http.StatusEarlyHints)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte("done"))
+		})(recorder, httptest.NewRequest("GET", "/", nil), nil)
+		if recorder.Code != http.StatusCreated || recorder.Body.String() != "done" {
+			t.Errorf("Expected the final status to be kept, got %d %q", recorder.Code, recorder.Body.String())
+		}
+	})
+
+	t.Run("slow_handler", func(t *testing.T) {
+		lateWrite := make(chan error, 1)
+		recorder := httptest.NewRecorder()
+		NewTimeout(10*time.Millisecond).GetHandler()(func(w http.ResponseWriter, r *http.Request, params *Param.Params) {
+			<-r.Context().Done()
+			time.Sleep(5 * time.Millisecond)
+			_, err := w.Write([]byte("too late"))
+			lateWrite <- err
+		})(recorder, httptest.NewRequest("GET", "/slow", nil), nil)
+
+		if recorder.Code != http.StatusServiceUnavailable {
+			t.Errorf("Expected 503, got %d", recorder.Code)
+		}
+		if recorder.Header().Get("Content-Type") != Error.ProblemContentType {
+			t.Errorf("Expected problem+json, got %q", recorder.Header().Get("Content-Type"))
+		}
+		if err := <-lateWrite; !errors.Is(err, http.ErrHandlerTimeout) {
+			t.Errorf("Expected late write to fail with ErrHandlerTimeout, got %v", err)
+		}
+	})
+
+	t.Run("route_override", func(t *testing.T) {
+		timeout := NewTimeout(5 * time.Millisecond)
+		timeout.Routes = map[string]time.Duration{"/reports/:id": time.Second, "/stream": 0}
+		for _, pattern := range []string{"/reports/:id", "/stream"} {
+			request := httptest.NewRequest("GET", "/", nil)
+			request.Pattern = pattern
+			recorder := httptest.NewRecorder()
+			timeout.GetHandler()(func(w http.ResponseWriter, r *http.Request, params *Param.Params) {
+				time.Sleep(20 * time.Millisecond)
+				w.WriteHeader(http.StatusOK)
+			})(recorder, request, nil)
+			if recorder.Code != http.StatusOK {
+				t.Errorf("%s: expected override to allow slow handler, got %d", pattern, recorder.Code)
+			}
+		}
+		if timeout.DurationFor("/other") != 5*time.Millisecond {
+			t.Error("Expected default duration for patterns without override")
+		}
+	})
+
+	t.Run("params_outlive_request", func(t *testing.T) {
+		params := Param.NewParams()
+		params.Path = "/users/42"
+		params.Add("id", 7, 9)
+		seen := make(chan string, 1)
+		NewTimeout(5*time.Millisecond).GetHandler()(func(w http.ResponseWriter, r *http.Request, handlerParams *Param.Params) {
+			<-r.Context().Done()
+			time.Sleep(5 * time.Millisecond)
+			seen <- handlerParams.GetByName("id")
+		})(httptest.NewRecorder(), httptest.NewRequest("GET", "/users/42", nil), params)
+		params.Reset() // What the tree's pool does once ServeHTTP returns
+		if id := <-seen; id != "42" {
+			t.Errorf("Expected handler to keep its params copy, got %q", id)
+		}
+	})
+
+	t.Run("panic_propagates", func(t *testing.T) {
+		var info PanicInfo
+		recovery := &Recovery{Hook: func(panicInfo PanicInfo) { info = panicInfo }, ErrorHandler: Error.WriteError}
+		recorder := httptest.NewRecorder()
+		recovery.GetHandler()(NewTimeout(time.Second).GetHandler()(func(w http.ResponseWriter, r *http.Request, params *Param.Params) {
+			panic("boom")
+		}))(recorder, httptest.NewRequest("GET", "/", nil), nil)
+		if info.Value != "boom" || recorder.Code != http.StatusInternalServerError {
+			t.Errorf("Expected outer Recovery to handle the panic, got %v %d", info.Value, recorder.Code)
+		}
+	})
+}
@@ -3,6 +3,7 @@
 package Param
 
 import (
+	"LiteFrame/Router/Error"
 	"context"
 	"sync"
 )
@@ -88,6 +89,32 @@ func (instance *Params) GetByName(name string) string {
 	return  ""
 }
 
+// Require returns the value of a required parameter.
+// Returns ParameterMissing error (matching Error.ErrParameterMissing) if the parameter
+// was not captured or is empty. Safe to call on nil Params.
+func (instance *Params) Require(name string) (string, error) {
+	if instance == nil {
+		return "", Error.NewError(Error.ParameterMissing, Error.GetErrorMessage(Error.ParameterMissing)+": "+name, "")
+	}
+	value := instance.GetByName(name)
+	if value == "" {
+		return "", Error.NewError(Error.ParameterMissing, Error.GetErrorMessage(Error.ParameterMissing)+": "+name, instance.Path)
+	}
+	return value, nil
+}
+
+// Clone returns a copy of the parameters that does not share memory with instance.
+// Used when parameters must outlive the request, since pooled Params are reused after it ends.
+// Returns nil for nil Params.
+func (instance *Params) Clone() *Params {
+	if instance == nil {
+		return nil
+	}
+	clone := *instance
+	clone.Overflow = append([]Param(nil), instance.Overflow...)
+	return &clone
+}
+
 // GetParamsFromCTX extracts parameters from context.
 // Gets parameters from request context in HTTP handlers.
 func GetParamsFromCTX(ctx context.Context) (*Params, bool) {
@@ -1,7 +1,9 @@
 package Param
 
 import (
+	"LiteFrame/Router/Error"
 	"context"
+	"errors"
 	"sync"
 	"testing"
 )
@@ -177,10 +179,65 @@ func TestParamsGetByName(t *testing.T) {
 	})
 }
 
+// ======================
+// Param Require Tests
+// ======================
+
+func TestParamsRequire(t *testing.T) {
+	params := NewParams()
+	params.Path = "/users/123"
+	params.Add("id", 7, 10)
+
+	t.Run("present", func(t *testing.T) {
+		value, err := params.Require("id")
+		if err != nil || value != "123" {
+			t.Errorf("Expected '123' without error, got %q, %v", value, err)
+		}
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		_, err := params.Require("name")
+		if !errors.Is(err, Error.ErrParameterMissing) {
+			t.Errorf("Expected ErrParameterMissing, got %v", err)
+		}
+	})
+
+	t.Run("nil_params", func(t *testing.T) {
+		var empty *Params
+		_, err := empty.Require("id")
+		if Error.Code(err) != Error.ParameterMissing {
+			t.Errorf("Expected ParameterMissing code, got %v", err)
+		}
+	})
+}
+
 // ======================
 // Context Tests
 // ======================
 
+func TestParamsClone(t *testing.T) {
+	var empty *Params
+	if empty.Clone() != nil {
+		t.Error("Expected nil clone of nil Params")
+	}
+
+	original := NewParams()
+	original.Path = "/a/b/c/d"
+	original.Add("first", 1, 2)
+	original.Add("second", 3, 4)
+	original.Add("third", 5, 6)
+
+	clone := original.Clone()
+	original.Reset()
+	original.Add("other", 7, 8)
+
+	for key, value := range map[string]string{"first": "a", "second": "b", "third": "c"} {
+		if got := clone.GetByName(key); got != value {
+			t.Errorf("Expected clone %s=%q, got %q", key, value, got)
+		}
+	}
+}
+
 func TestGetParamsFromCTX(t *testing.T) {
 	t.Run("existing_params_in_context", func(t *testing.T) {
 		params := NewParams()
@@ -3,35 +3,71 @@
 package Router
 
 import (
+	"LiteFrame/Router/Error"
+	"LiteFrame/Router/Middleware"
+	"LiteFrame/Router/Types"
 	"net/http"
+	"strings"
 )
 
 // Router is the basic HTTP router structure.
 // It includes default handlers for 404 (Not Found) and 405 (Method Not Allowed) errors.
 // It will be integrated with the Tree router in the future to form a complete routing system.
 type Router struct {
-	NotFoundHandler   http.HandlerFunc // 404 error handler
-	NotAllowedHandler http.HandlerFunc // 405 error handler
+	NotFoundHandler   http.HandlerFunc        // 404 error handler
+	NotAllowedHandler http.HandlerFunc        // 405 error handler
+	ErrorHandler      Types.ErrorHandlerFunc  // Central handler for errors returned by handlers
+	Methods           Middleware.MethodLister // Source of the Allow header in 405 responses (e.g. a Tree)
 }
 
 // NotFoundDefault is the default 404 error handler.
-// It returns a standard HTTP 404 response when the requested resource cannot be found.
+// It returns an RFC 9457 application/problem+json response when the requested resource cannot be found.
 func NotFoundDefault(writer http.ResponseWriter, request *http.Request) {
-	http.Error(writer, "404 Not Found", http.StatusNotFound)
+	Error.WriteError(writer, request, Error.NewErrorWithCode(Error.HandlerNotFound, request.URL.Path))
 }
 
 // NotAllowedDefault is the default 405 error handler.
-// It returns a standard HTTP 405 response when a request is made with an unsupported HTTP method.
+// It returns an RFC 9457 application/problem+json response when a request is made with an unsupported HTTP method.
+// RFC 9110 requires an Allow header in every 405 response; it is kept if already set and
+// written empty (no methods allowed) otherwise. Router.NotAllowed sets it from Methods.
 func NotAllowedDefault(writer http.ResponseWriter, request *http.Request) {
-	http.Error(writer, "Method Not Allowed", http.StatusMethodNotAllowed)
+	if _, ok := writer.Header()["Allow"]; !ok {
+		writer.Header().Set("Allow", "")
+	}
+	Error.WriteError(writer, request, Error.NewErrorWithCode(Error.MethodNotAllowed, request.URL.Path))
+}
+
+// NotAllowed is the 405 handler installed by NewRouter.
+// Sets the Allow header to the methods Methods reports for the request path, then calls NotAllowedDefault.
+func (instance *Router) NotAllowed(writer http.ResponseWriter, request *http.Request) {
+	if instance.Methods != nil {
+		writer.Header().Set("Allow", strings.Join(instance.Methods.AllowedMethods(request.URL.Path), ", "))
+	}
+	NotAllowedDefault(writer, request)
+}
+
+// Adapt converts an error-returning handler into an http.HandlerFunc.
+// Returned errors are passed to the router's ErrorHandler (Error.WriteError if unset).
+func (instance *Router) Adapt(handler Types.HandlerFuncWithError) http.HandlerFunc {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		if err := handler(writer, request, nil); err != nil {
+			if instance.ErrorHandler != nil {
+				instance.ErrorHandler(writer, request, err)
+				return
+			}
+			Error.WriteError(writer, request, err)
+		}
+	}
 }
 
 // NewRouter creates a new Router instance.
 // It initializes the router with default error handlers.
 // Users can replace them with custom error handlers as needed.
 func NewRouter() *Router {
-	return &Router{
-		NotFoundHandler:   NotFoundDefault,   // Set default 404 handler
-		NotAllowedHandler: NotAllowedDefault, // Set default 405 handler
+	router := &Router{
+		NotFoundHandler: NotFoundDefault,  // Set default 404 handler
+		ErrorHandler:    Error.WriteError, // Set default problem+json error handler
 	}
+	router.NotAllowedHandler = router.NotAllowed // Set default 405 handler with Allow header
+	return router
 }
@@ -0,0 +1,91 @@
+// Package Tree provides batch route registration with atomic rollback.
+// Registers many routes at once, collecting every failure instead of stopping at the first.
+package Tree
+
+import (
+	"errors"
+	"fmt"
+)
+
+// RouteError wraps a registration error with the position of the failing route in a batch.
+// Unwrap exposes the underlying LiteFrameError to errors.As.
+type RouteError struct {
+	Index   int    // Index of the route in the batch
+	Method  string // HTTP method of the route
+	Pattern string // Route pattern as given
+	Err     error  // Error returned by SetHandler
+}
+
+// Error implements the error interface.
+func (instance *RouteError) Error() string {
+	return fmt.Sprintf("route %d (%s %s): %v", instance.Index, instance.Method, instance.Pattern, instance.Err)
+}
+
+// Unwrap returns the underlying registration error.
+func (instance *RouteError) Unwrap() error {
+	return instance.Err
+}
+
+// CloneNode returns a deep copy of node and its subtree.
+// Handler functions are shared, node structure is not.
+func (instance *Tree) CloneNode(node *Node) *Node {
+	clone := *node
+	clone.Indices = append([]byte(nil), node.Indices...)
+	clone.Handlers = append([]HandlerFunc(nil), node.Handlers...)
+	clone.Children = make([]*Node, len(node.Children))
+	for index, child := range node.Children {
+		clone.Children[index] = instance.CloneNode(child)
+	}
+	if node.WildCard != nil {
+		clone.WildCard = instance.CloneNode(node.WildCard)
+	}
+	if node.CatchAll != nil {
+		clone.CatchAll = instance.CloneNode(node.CatchAll)
+	}
+	return &clone
+}
+
+// RegisterAll registers all routes atomically.
+// Every route is applied to a copy of the tree so that all failures can be reported;
+// the copy replaces the live tree only if no route failed.
+//
+// Returns: nil on success, otherwise errors.Join of *RouteError values (one per failing route)
+// and the tree is left unchanged.
+func (instance *Tree) RegisterAll(routes []Route) error {
+	scratch := *instance
+	scratch.RootNode = instance.CloneNode(instance.RootNode)
+
+	var failures []error
+	for index, route := range routes {
+		if err := scratch.SetHandler(scratch.StringToMethodType(route.Method), route.Pattern, route.Handler); err != nil {
+			failures = append(failures, &RouteError{Index: index, Method: route.Method, Pattern: route.Pattern, Err: err})
+		}
+	}
+	if len(failures) > 0 {
+		return errors.Join(failures...)
+	}
+	instance.RootNode = scratch.RootNode
+	return nil
+}
+
+// Batch collects routes to be registered together through RegisterAll.
+type Batch struct {
+	Tree   *Tree   // Target tree
+	Routes []Route // Routes in registration order
+}
+
+// NewBatch creates an empty Batch for the tree.
+func (instance *Tree) NewBatch() *Batch {
+	return &Batch{Tree: instance, Routes: make([]Route, 0)}
+}
+
+// Add appends a route to the batch and returns the batch for chaining.
+func (instance *Batch) Add(method string, pattern string, handler HandlerFunc) *Batch {
+	instance.Routes = append(instance.Routes, Route{Method: method, Pattern: pattern, Handler: handler})
+	return instance
+}
+
+// Commit registers all collected routes atomically. See Tree.RegisterAll.
+func (instance *Batch) Commit() error {
+	return instance.Tree.RegisterAll(instance.Routes)
+}
@@ -0,0 +1,189 @@
+// Package Tree provides tree visualization export for debugging.
+// Renders the Radix Tree as indented text, Graphviz DOT or JSON for bug reports and golden tests.
+package Tree
+
+import (
+	"LiteFrame/Router/Error"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// NodeInfo is a serializable snapshot of a single node and its subtree.
+// Mirrors Node fields with handlers replaced by the list of registered method names.
+type NodeInfo struct {
+	Type     string     `json:"type"`               // Node type name (root, static, wildcard, catchall)
+	Path     string     `json:"path"`               // Compressed path segment
+	Indices  string     `json:"indices,omitempty"`  // First bytes of static children
+	Param    string     `json:"param,omitempty"`    // Parameter name for WildCard/CatchAll nodes
+	Partial  bool       `json:"partial,omitempty"`  // Path continues the parent's segment
+	Methods  []string   `json:"methods,omitempty"`  // Methods with registered handlers
+	Children []NodeInfo `json:"children,omitempty"` // Static children in Indices order
+	WildCard *NodeInfo  `json:"wildcard,omitempty"` // WildCard child
+	CatchAll *NodeInfo  `json:"catchall,omitempty"` // CatchAll child
+}
+
+// NodeTypeToString converts NodeType to its lowercase display name.
+//
+//go:inline
+func (instance *Tree) NodeTypeToString(nodeType NodeType) string {
+	switch nodeType {
+	case RootType:
+		return "root"
+	case StaticType:
+		return "static"
+	case WildCardType:
+		return "wildcard"
+	case CatchAllType:
+		return "catchall"
+	default:
+		return "unknown"
+	}
+}
+
+// Methods returns names of methods that have a handler on node, in MethodType order.
+func (instance *Tree) Methods(node *Node) []string {
+	methods := make([]string, 0, len(node.Handlers))
+	for method, handler := range node.Handlers {
+		if handler != nil {
+			methods = append(methods, instance.MethodTypeToString(MethodType(method)))
+		}
+	}
+	return methods
+}
+
+// Describe builds a NodeInfo snapshot of node and its whole subtree.
+func (instance *Tree) Describe(node *Node) NodeInfo {
+	info := NodeInfo{
+		Type:    instance.NodeTypeToString(node.Type),
+		Path:    node.Path,
+		Indices: string(node.Indices),
+		Param:   node.Param,
+		Partial: node.Partial,
+		Methods: instance.Methods(node),
+	}
+	for _, child := range node.Children {
+		info.Children = append(info.Children, instance.Describe(child))
+	}
+	if node.WildCard != nil {
+		wildCard := instance.Describe(node.WildCard)
+		info.WildCard = &wildCard
+	}
+	if node.CatchAll != nil {
+		catchAll := instance.Describe(node.CatchAll)
+		info.CatchAll = &catchAll
+	}
+	return info
+}
+
+// Dump writes the tree structure to writer in the requested format.
+// Returns InvalidParameter error for unknown formats and any error from writer.
+func (instance *Tree) Dump(writer io.Writer, format DumpFormat) error {
+	info := instance.Describe(instance.RootNode)
+	switch format {
+	case DumpText:
+		return instance.DumpText(writer, info, 0)
+	case DumpDOT:
+		return instance.DumpDOT(writer, info)
+	case DumpJSON:
+		encoder := json.NewEncoder(writer)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(info)
+	default:
+		return Error.NewError(Error.InvalidParameter, fmt.Sprintf("Unknown dump format %d", format), "")
+	}
+}
+
+// DumpText writes info as indented text, two spaces per depth level.
+//
+// Line format: <type> "<path>" [partial] [param=<name>] [indices="<bytes>"] [methods=<A,B>]
+func (instance *Tree) DumpText(writer io.Writer, info NodeInfo, depth int) error {
+	var line strings.Builder
+	line.WriteString(strings.Repeat("  ", depth))
+	fmt.Fprintf(&line, "%s %q", info.Type, info.Path)
+	if info.Partial {
+		line.WriteString(" partial")
+	}
+	if info.Param != "" {
+		fmt.Fprintf(&line, " param=%s", info.Param)
+	}
+	if info.Indices != "" {
+		fmt.Fprintf(&line, " indices=%q", info.Indices)
+	}
+	if len(info.Methods) > 0 {
+		fmt.Fprintf(&line, " methods=%s", strings.Join(info.Methods, ","))
+	}
+	line.WriteByte('\n')
+	if _, err := io.WriteString(writer, line.String()); err != nil {
+		return err
+	}
+	for _, child := range info.Children {
+		if err := instance.DumpText(writer, child, depth+1); err != nil {
+			return err
+		}
+	}
+	if info.WildCard != nil {
+		if err := instance.DumpText(writer, *info.WildCard, depth+1); err != nil {
+			return err
+		}
+	}
+	if info.CatchAll != nil {
+		if err := instance.DumpText(writer, *info.CatchAll, depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DumpDOT writes info as a Graphviz digraph.
+// Nodes are numbered in depth-first order; static edges are labeled with their index byte,
+// WildCard and CatchAll edges with ':' and '*'. Nodes with handlers are drawn bold.
+func (instance *Tree) DumpDOT(writer io.Writer, info NodeInfo) error {
+	var builder strings.Builder
+	builder.WriteString("digraph Tree {\n")
+	builder.WriteString("  node [shape=box, fontname=\"monospace\"];\n")
+	counter := 0
+	instance.WriteDOTNode(&builder, info, &counter)
+	builder.WriteString("}\n")
+	_, err := io.WriteString(writer, builder.String())
+	return err
+}
+
+// WriteDOTNode writes node statement and outgoing edges of info, returning its DOT id.
+func (instance *Tree) WriteDOTNode(builder *strings.Builder, info NodeInfo, counter *int) string {
+	id := fmt.Sprintf("n%d", *counter)
+	*counter++
+
+	label := info.Type + "\\n" + DOTEscape(info.Path)
+	if info.Partial {
+		label += " (partial)"
+	}
+	if len(info.Methods) > 0 {
+		label += "\\n" + strings.Join(info.Methods, ",")
+	}
+	style := ""
+	if len(info.Methods) > 0 {
+		style = ", style=bold"
+	}
+	fmt.Fprintf(builder, "  %s [label=\"%s\"%s];\n", id, label, style)
+
+	for index, child := range info.Children {
+		childID := instance.WriteDOTNode(builder, child, counter)
+		fmt.Fprintf(builder, "  %s -> %s [label=\"%s\"];\n", id, childID, DOTEscape(string(info.Indices[index])))
+	}
+	if info.WildCard != nil {
+		childID := instance.WriteDOTNode(builder, *info.WildCard, counter)
+		fmt.Fprintf(builder, "  %s -> %s [label=\"%c\", style=dashed];\n", id, childID, WildCardPrefix)
+	}
+	if info.CatchAll != nil {
+		childID := instance.WriteDOTNode(builder, *info.CatchAll, counter)
+		fmt.Fprintf(builder, "  %s -> %s [label=\"%c\", style=dotted];\n", id, childID, CatchAllPrefix)
+	}
+	return id
+}
+
+// DOTEscape escapes characters that are special inside DOT double-quoted strings.
+func DOTEscape(input string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(input)
+}
@@ -0,0 +1,41 @@
+// Package Tree provides error-returning handler support.
+// Adapts HandlerFuncWithError to HandlerFunc and routes returned errors to the tree's ErrorHandler.
+package Tree
+
+import (
+	"LiteFrame/Router/Error"
+	"LiteFrame/Router/Param"
+	"net/http"
+)
+
+// HandleError passes err to the tree's ErrorHandler.
+// Falls back to Error.WriteError (problem+json) when no ErrorHandler is configured.
+// Also usable from plain HandlerFunc handlers and middleware to respond consistently.
+func (instance *Tree) HandleError(writer http.ResponseWriter, request *http.Request, err error) {
+	if instance.ErrorHandler != nil {
+		instance.ErrorHandler(writer, request, err)
+		return
+	}
+	Error.WriteError(writer, request, err)
+}
+
+// Adapt converts an error-returning handler into a HandlerFunc.
+// A non-nil returned error is handed to HandleError. The ErrorHandler is looked up on every
+// call, so it can be replaced after routes are registered.
+// The handler must not write a response itself before returning an error.
+func (instance *Tree) Adapt(handler HandlerFuncWithError) HandlerFunc {
+	return func(writer http.ResponseWriter, request *http.Request, params *Param.Params) {
+		if err := handler(writer, request, params); err != nil {
+			instance.HandleError(writer, request, err)
+		}
+	}
+}
+
+// SetHandlerWithError registers an error-returning handler for path and method.
+// See Adapt for how returned errors are handled.
+func (instance *Tree) SetHandlerWithError(method MethodType, rawPath string, handler HandlerFuncWithError) error {
+	if handler == nil {
+		return Error.NewErrorWithCode(Error.InvalidHandler, rawPath)
+	}
+	return instance.SetHandler(method, rawPath, instance.Adapt(handler))
+}
@@ -0,0 +1,156 @@
+// Package Tree provides route matching explanation for debugging.
+// Records every decision GetHandler's matching algorithm makes through a search trace.
+package Tree
+
+import (
+	"LiteFrame/Router/Param"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// ExplainStep records a single candidate examined while matching a path.
+type ExplainStep struct {
+	Kind    string `json:"kind"`    // Candidate node type (static, wildcard, catchall)
+	Node    string `json:"node"`    // Compressed path of the candidate node (empty if absent)
+	Pattern string `json:"pattern"` // Full pattern of the candidate node (empty if absent)
+	Segment string `json:"segment"` // Input being matched at this step
+	Result  string `json:"result"`  // StepMatched, StepPartial, StepMismatch or StepAbsent
+}
+
+// CapturedParam is a parameter value captured while matching a path.
+type CapturedParam struct {
+	Key   string `json:"key"`   // Parameter name
+	Value string `json:"value"` // Captured value
+}
+
+// Explanation is the full trace of how the tree resolved a method and path.
+type Explanation struct {
+	Method  string          `json:"method"`            // Requested HTTP method
+	Path    string          `json:"path"`              // Requested URL path
+	Steps   []ExplainStep   `json:"steps"`             // Candidates examined in order
+	Params  []CapturedParam `json:"params,omitempty"`  // Parameters captured on the way
+	Pattern string          `json:"pattern,omitempty"` // Pattern of the last node reached
+	Status  MatchStatus     `json:"status"`            // Final decision
+	Handler HandlerFunc     `json:"-"`                 // Handler GetHandler would return
+}
+
+// String returns the lowercase name of the match status.
+func (instance MatchStatus) String() string {
+	switch instance {
+	case MatchFound:
+		return "found"
+	case MatchNotFound:
+		return "not-found"
+	case MatchMethodNotAllowed:
+		return "method-not-allowed"
+	default:
+		return "unknown"
+	}
+}
+
+// MarshalText encodes the match status as its name so JSON output stays readable.
+func (instance MatchStatus) MarshalText() ([]byte, error) {
+	return []byte(instance.String()), nil
+}
+
+// String renders the explanation as human readable multi-line text.
+func (instance *Explanation) String() string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "%s %s\n", instance.Method, instance.Path)
+	for index, step := range instance.Steps {
+		fmt.Fprintf(&builder, "  %d. %-8s %-9s segment=%q", index+1, step.Kind, step.Result, step.Segment)
+		if step.Pattern != "" {
+			fmt.Fprintf(&builder, " pattern=%s", step.Pattern)
+		}
+		builder.WriteByte('\n')
+	}
+	for _, param := range instance.Params {
+		fmt.Fprintf(&builder, "  param %s=%q\n", param.Key, param.Value)
+	}
+	fmt.Fprintf(&builder, "  => %s", instance.Status)
+	if instance.Pattern != "" {
+		fmt.Fprintf(&builder, " (%s)", instance.Pattern)
+	}
+	builder.WriteByte('\n')
+	return builder.String()
+}
+
+// Decide returns the handler and status for method on the node where matching ended.
+// Mirrors SelectHandler's decision.
+func (instance *Tree) Decide(node *Node, method MethodType) (HandlerFunc, MatchStatus) {
+	if handler := node.Handlers[method]; handler != nil {
+		return handler, MatchFound
+	}
+	if node.HasHandler() {
+		return instance.NotAllowedHandler, MatchMethodNotAllowed
+	}
+	return instance.NotFoundHandler, MatchNotFound
+}
+
+// Explain resolves method and path exactly like GetHandler and reports every step taken.
+// The steps are recorded by TraceSearch, the matching routine GetHandler itself uses.
+// Intended for tests and debug endpoints; it allocates and is not meant for the hot path.
+func (instance *Tree) Explain(method string, rawPath string) *Explanation {
+	explanation := &Explanation{Method: method, Path: rawPath, Steps: make([]ExplainStep, 0)}
+	methodType := instance.StringToMethodType(method)
+	if methodType == NotAllowed {
+		explanation.Status = MatchMethodNotAllowed
+		explanation.Handler = instance.NotAllowedHandler
+		return explanation
+	}
+
+	pattern := "/"
+	node, params := instance.TraceSearch(rawPath, Param.NewParams, func(kind NodeType, node *Node, segment string, result string) {
+		step := ExplainStep{Kind: instance.NodeTypeToString(kind), Segment: segment, Result: result}
+		if node != nil {
+			step.Node = node.Path
+			step.Pattern = instance.JoinPattern(pattern, node)
+			// Matched and partial candidates are descended into
+			if result == StepMatched || result == StepPartial {
+				pattern = step.Pattern
+			}
+		}
+		explanation.Steps = append(explanation.Steps, step)
+	})
+	if params != nil {
+		for _, param := range slices.Concat(params.Fix[:min(params.Count, Param.DefaultSize)], params.Overflow) {
+			explanation.Params = append(explanation.Params, CapturedParam{Key: param.Key, Value: params.Path[param.Start:param.End]})
+		}
+	}
+	if node == nil {
+		explanation.Status = MatchNotFound
+		explanation.Handler = instance.NotFoundHandler
+		return explanation
+	}
+	explanation.Pattern = pattern
+	explanation.Handler, explanation.Status = instance.Decide(node, methodType)
+	return explanation
+}
+
+// ExplainHandler returns a debug handler that explains the route matching of a query.
+// Query parameters: method (default GET) and path (default "/").
+// Responds with JSON, or plain text when format=text is given.
+func (instance *Tree) ExplainHandler() HandlerFunc {
+	return func(writer http.ResponseWriter, request *http.Request, _ *Param.Params) {
+		query := request.URL.Query()
+		method := query.Get("method")
+		if method == "" {
+			method = http.MethodGet
+		}
+		path := query.Get("path")
+		if path == "" {
+			path = "/"
+		}
+		explanation := instance.Explain(method, path)
+		if query.Get("format") == "text" {
+			writer.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			_, _ = writer.Write([]byte(explanation.String()))
+			return
+		}
+		writer.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(writer).Encode(explanation)
+	}
+}
@@ -0,0 +1,138 @@
+// Package Tree provides route groups with shared prefixes and middleware.
+// Groups apply middleware to a subset of routes by wrapping handlers at registration time.
+package Tree
+
+import (
+	"LiteFrame/Router/Error"
+	"LiteFrame/Router/Middleware"
+	"slices"
+)
+
+// Group registers routes under a common prefix with group-level middleware.
+// Group middleware runs inside the tree-wide middleware, after routing, so
+// request.Pattern is already set. The first middleware is the outermost one.
+//
+// Each middleware's GetHandler is called once, when it is added to the group, and the result
+// is shared by every route of the group and its nested groups. State a middleware prepares
+// in GetHandler (rate limit stores, semaphores, pools) is therefore group-wide.
+//
+// Middleware added with Use only applies to routes registered afterwards.
+//
+// Requests under the prefix that match no route (404) or no method of their route (405)
+// also run through the chain of the deepest group whose prefix they match, so CORS answers
+// preflights and IPFilter, authentication or rate limiting apply to every path of the group.
+// When several groups share that prefix, the first one created is used.
+//
+// Usage example:
+//
+//	api := tree.Group("/api", Middleware.NewCORS(tree))
+//	admin := api.Group("/admin", adminOnly)
+//	admin.SetHandler(Tree.GET, "/stats", statsHandler) // GET /api/admin/stats
+type Group struct {
+	Tree        *Tree                   // Tree receiving the routes
+	Prefix      string                  // Cleaned path prefix of the group
+	Middlewares []Middleware.Middleware // Middleware applied to routes of the group
+
+	chain []Middleware.MiddleWareFunc // Built Middlewares, parallel to Middlewares
+}
+
+// Group creates a route group for prefix with the given middleware.
+func (instance *Tree) Group(prefix string, middlewares ...Middleware.Middleware) *Group {
+	group := &Group{
+		Tree:   instance,
+		Prefix: CleanPath(prefix),
+	}
+	instance.groups = append(instance.groups, group)
+	return group.Use(middlewares...)
+}
+
+// Group creates a nested group; its prefix and middleware extend the parent's.
+func (instance *Group) Group(prefix string, middlewares ...Middleware.Middleware) *Group {
+	// The parent's chain is reused, not rebuilt, so both groups share middleware state
+	group := &Group{
+		Tree:        instance.Tree,
+		Prefix:      instance.Join(prefix),
+		Middlewares: slices.Clone(instance.Middlewares),
+		chain:       slices.Clone(instance.chain),
+	}
+	instance.Tree.groups = append(instance.Tree.groups, group)
+	return group.Use(middlewares...)
+}
+
+// Use appends middleware to the group and builds it.
+func (instance *Group) Use(middlewares ...Middleware.Middleware) *Group {
+	for _, middleware := range middlewares {
+		instance.Middlewares = append(instance.Middlewares, middleware)
+		instance.chain = append(instance.chain, middleware.GetHandler())
+	}
+	return instance
+}
+
+// Join returns the full pattern of rawPath inside the group.
+func (instance *Group) Join(rawPath string) string {
+	if instance.Prefix == string(PathSeparator) {
+		return CleanPath(rawPath)
+	}
+	return CleanPath(instance.Prefix + string(PathSeparator) + rawPath)
+}
+
+// Match reports whether rawPath lies under the group prefix and returns the number of
+// prefix segments matched. Parameters in the prefix match any segment, a catch-all the rest.
+func (instance *Group) Match(rawPath string) (bool, int) {
+	prefix := NewPathWithSegment(instance.Prefix)
+	path := NewPathWithSegment(rawPath)
+	segments := 0
+	for prefix.Next(); !prefix.IsSame(); prefix.Next() {
+		segment := prefix.Path[prefix.Start:prefix.End]
+		if instance.Tree.IsCatchAll(segment) {
+			return true, segments + 1
+		}
+		path.Next()
+		if path.IsSame() {
+			return false, 0
+		}
+		if !instance.Tree.IsWildCard(segment) && segment != path.Path[path.Start:path.End] {
+			return false, 0
+		}
+		segments++
+	}
+	return true, segments
+}
+
+// GroupFor returns the deepest group whose prefix rawPath lies under, or nil.
+func (instance *Tree) GroupFor(rawPath string) *Group {
+	var found *Group
+	depth := -1
+	for _, group := range instance.groups {
+		if matched, segments := group.Match(rawPath); matched && segments > depth {
+			found, depth = group, segments
+		}
+	}
+	return found
+}
+
+// Wrap applies the group middleware to handler.
+func (instance *Group) Wrap(handler HandlerFunc) HandlerFunc {
+	for index := len(instance.chain) - 1; index > -1; index-- {
+		handler = instance.chain[index](handler)
+	}
+	return handler
+}
+
+// SetHandler registers handler for method and the group-relative path.
+// Validation matches Tree.SetHandler: nil handlers are only accepted for CONNECT.
+func (instance *Group) SetHandler(method MethodType, rawPath string, handler HandlerFunc) error {
+	if handler != nil {
+		handler = instance.Wrap(handler)
+	}
+	return instance.Tree.SetHandler(method, instance.Join(rawPath), handler)
+}
+
+// SetHandlerWithError registers an error-returning handler for method and the group-relative path.
+// Returned errors go to the tree's ErrorHandler, see Tree.Adapt.
+func (instance *Group) SetHandlerWithError(method MethodType, rawPath string, handler HandlerFuncWithError) error {
+	if handler == nil {
+		return Error.NewErrorWithCode(Error.InvalidHandler, instance.Join(rawPath))
+	}
+	return instance.SetHandler(method, rawPath, instance.Tree.Adapt(handler))
+}
@@ -0,0 +1,38 @@
+// Package Tree provides request-free route resolution.
+// Resolves a method and path to handler, parameters and route pattern for tests, gateways and CLIs.
+package Tree
+
+import (
+	"LiteFrame/Router/Param"
+)
+
+// Lookup resolves method and path without building an *http.Request.
+// Uses the same matching algorithm as GetHandler. Parameters are freshly allocated
+// instead of taken from the pool, so the result can be kept by the caller.
+func (instance *Tree) Lookup(method string, path string) LookupResult {
+	methodType := instance.StringToMethodType(method)
+	if methodType == NotAllowed {
+		return LookupResult{Handler: instance.NotAllowedHandler, Status: MatchMethodNotAllowed}
+	}
+	node, params := instance.Search(path, Param.NewParams)
+	if node == nil {
+		return LookupResult{Handler: instance.NotFoundHandler, Status: MatchNotFound}
+	}
+	result := LookupResult{Params: params, Pattern: node.Pattern}
+	result.Handler, result.Status = instance.Decide(node, methodType)
+	if result.Status == MatchNotFound {
+		result.Params = nil
+	}
+	return result
+}
+
+// AllowedMethods returns the methods registered on the node matching path, in MethodType order.
+// Returns nil if no route matches path or the matched node has no handlers.
+// Implements Middleware.MethodLister so CORS preflights reflect the actual routes.
+func (instance *Tree) AllowedMethods(path string) []string {
+	node, _ := instance.Search(path, Param.NewParams)
+	if node == nil || !node.HasHandler() {
+		return nil
+	}
+	return instance.Methods(node)
+}
@@ -33,4 +33,61 @@ type Node struct {
 	WildCard *Node         // Wildcard child node (:param, single segment matching)
 	CatchAll *Node         // CatchAll child node (*path, remaining all path matching)
 	Param    string        // Parameter name (used only in WildCard/CatchAll nodes, excluding ':' '*')
+	Partial  bool          // Path continues the parent's segment instead of starting a new one
+	Pattern  string        // Full route pattern registered on this node (empty if node has no handlers)
+}
+
+// HasHandler reports whether any HTTP method has a handler registered on the node.
+// Distinguishes route endpoints (405 on method mismatch) from intermediate nodes (404).
+func (instance *Node) HasHandler() bool {
+	for _, handler := range instance.Handlers {
+		if handler != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// FindChild returns the index of the static child whose path starts with first and which
+// continues the current segment (partial) or starts a new one (!partial). Returns -1 if none.
+// A first byte appears in Indices at most twice, once for each kind of child.
+// Keeping both kinds apart stops /users and /user/s from sharing nodes across a separator.
+func (instance *Node) FindChild(first byte, partial bool) int {
+	low, high := 0, len(instance.Indices)-1
+	for low <= high {
+		mid := (low + high) >> 1
+		if instance.Indices[mid] < first {
+			low = mid + 1
+		} else {
+			high = mid - 1
+		}
+	}
+	for index := low; index < len(instance.Indices) && instance.Indices[index] == first; index++ {
+		if instance.Children[index].Partial == partial {
+			return index
+		}
+	}
+	return -1
+}
+
+// FirstPattern returns the first route pattern registered in the node's subtree.
+// Used to name the existing route when reporting conflicts. Returns empty string if none.
+func (instance *Node) FirstPattern() string {
+	if instance.Pattern != "" {
+		return instance.Pattern
+	}
+	for _, child := range instance.Children {
+		if pattern := child.FirstPattern(); pattern != "" {
+			return pattern
+		}
+	}
+	if instance.WildCard != nil {
+		if pattern := instance.WildCard.FirstPattern(); pattern != "" {
+			return pattern
+		}
+	}
+	if instance.CatchAll != nil {
+		return instance.CatchAll.FirstPattern()
+	}
+	return ""
 }
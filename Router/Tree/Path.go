@@ -2,6 +2,8 @@
 // A zero-allocation structure for iterating through URL paths segment by segment.
 package Tree
 
+import "strings"
+
 // NewPathWithSegment creates a new PathWithSegment instance.
 // Path: URL path string to analyze
 // Initial state has both Start and End set to 0.
@@ -73,10 +75,33 @@ func (instance *PathWithSegment) IsSame() bool {
 	return instance.Start == instance.End
 }
 
-
+// IsBoundary reports whether the current position starts a segment
+// instead of continuing one that was partially matched.
+//
+//go:inline
+func (instance *PathWithSegment) IsBoundary() bool {
+	return instance.Start == 0 || instance.Path[instance.Start-1] == PathSeparator
+}
 
 // GetLength returns the length of the current segment.
 // Memory efficient as it calculates length without creating strings.
 func (instance *PathWithSegment) GetLength() int {
 	return instance.End - instance.Start
 }
+
+// CleanPath returns the canonical form of a path or route pattern.
+// Collapses repeated separators, drops the trailing separator and guarantees a leading one,
+// so "//users//:id/" becomes "/users/:id". Empty input becomes "/".
+func CleanPath(rawPath string) string {
+	path := NewPathWithSegment(rawPath)
+	var builder strings.Builder
+	builder.Grow(len(rawPath) + 1)
+	for path.Next(); !path.IsSame(); path.Next() {
+		builder.WriteByte(PathSeparator)
+		builder.WriteString(path.Path[path.Start:path.End])
+	}
+	if builder.Len() == 0 {
+		return string(PathSeparator)
+	}
+	return builder.String()
+}
@@ -7,7 +7,9 @@ import (
 	"LiteFrame/Router/Middleware"
 	"LiteFrame/Router/Param"	
 	"net/http"
+	"slices"
 	"sort"
+	"strings"
 )
 
 // Tree is a Radix Tree structure for HTTP routing.
@@ -17,19 +19,41 @@ type Tree struct {
 	Pool               *Param.ParamsPool       // Pool for parameter reuse
 	NotFoundHandler    HandlerFunc             // 404 handler
 	NotAllowedHandler  HandlerFunc             // 405 handler
+	ErrorHandler       ErrorHandlerFunc        // Central handler for handler and middleware errors (nil: Error.WriteError)
 	Middlewares        []Middleware.Middleware // Middleware list
 	CompiledMiddleware func(HandlerFunc) HandlerFunc
+	MaxPathLength      int // Maximum route pattern length accepted by SetHandler (0: unlimited)
+	MaxSegments        int // Maximum route pattern segment count accepted by SetHandler (0: unlimited)
+
+	groups []*Group // Groups created on the tree, for requests under a prefix that match no route
 }
 
 // NewTree creates a new Tree instance.
-// Initializes root node and parameter pool.
+// Initializes root node, parameter pool, default 404/405 handlers and default route validation limits.
 func NewTree() Tree {
 	return Tree{
-		RootNode: NewNode(RootType, "/"),
-		Pool:     Param.NewParamsPool(),
+		RootNode:          NewNode(RootType, "/"),
+		Pool:              Param.NewParamsPool(),
+		NotFoundHandler:   NotFoundDefault,
+		NotAllowedHandler: NotAllowedDefault,
+		MaxPathLength:     DefaultMaxPathLength,
+		MaxSegments:       DefaultMaxSegments,
 	}
 }
 
+// NotFoundDefault is the default 404 handler of the tree.
+// Responds with an application/problem+json document for HandlerNotFound.
+func NotFoundDefault(writer http.ResponseWriter, request *http.Request, _ *Param.Params) {
+	Error.WriteError(writer, request, Error.NewErrorWithCode(Error.HandlerNotFound, request.URL.Path))
+}
+
+// NotAllowedDefault is the default 405 handler of the tree.
+// Responds with an application/problem+json document for MethodNotAllowed.
+// The Allow header is already set by the tree when a 405 handler runs, see Tree.NotAllowed.
+func NotAllowedDefault(writer http.ResponseWriter, request *http.Request, _ *Param.Params) {
+	Error.WriteError(writer, request, Error.NewErrorWithCode(Error.MethodNotAllowed, request.URL.Path))
+}
+
 // IsWildCard checks if input string is a wildcard pattern (:param).
 //
 //go:inline
@@ -73,6 +97,35 @@ func (instance *Tree) StringToMethodType(method string) MethodType {
 	}
 }
 
+// MethodTypeToString converts MethodType back to its HTTP method string.
+// Returns empty string for NotAllowed and unknown values.
+//
+//go:inline
+func (instance *Tree) MethodTypeToString(method MethodType) string {
+	switch method {
+	case GET:
+		return "GET"
+	case HEAD:
+		return "HEAD"
+	case OPTIONS:
+		return "OPTIONS"
+	case TRACE:
+		return "TRACE"
+	case POST:
+		return "POST"
+	case PUT:
+		return "PUT"
+	case DELETE:
+		return "DELETE"
+	case CONNECT:
+		return "CONNECT"
+	case PATCH:
+		return "PATCH"
+	default:
+		return ""
+	}
+}
+
 // Match finds common prefix between PathWithSegment and string, returning match results.
 // Returns: (complete match, matched index, remaining PathWithSegment)
 // Optimized matching algorithm based on PathWithSegment.
@@ -102,7 +155,8 @@ func (instance *Tree) Match(sourcePath PathWithSegment, targetPath string) (bool
 }
 
 // SelectHandler selects method-appropriate handler from node and injects parameters into context.
-// Returns NotAllowedHandler if handler not found.
+// Returns NotAllowedHandler (through NotAllowed) if node has handlers for other methods only,
+// and NotFoundHandler if node is an intermediate node without any handler.
 // Important: Core function handling both memory pool management and context injection.
 //go:noinline
 func (instance *Tree) SelectHandler(node *Node, method MethodType) HandlerFunc {
@@ -110,7 +164,24 @@ func (instance *Tree) SelectHandler(node *Node, method MethodType) HandlerFunc {
 		// Inject parameters into context through closure and ensure memory pool return
 		return handler
 	}
-	return instance.NotAllowedHandler
+	if node.HasHandler() {
+		return instance.NotAllowed(node)
+	}
+	return instance.NotFoundHandler
+}
+
+// NotAllowed returns NotAllowedHandler preceded by setting the Allow header to the methods
+// registered on node, which RFC 9110 requires in every 405 response. A nil node (or one
+// without handlers) yields an empty Allow header: the resource allows no methods.
+func (instance *Tree) NotAllowed(node *Node) HandlerFunc {
+	allow := ""
+	if node != nil {
+		allow = strings.Join(instance.Methods(node), ", ")
+	}
+	return func(writer http.ResponseWriter, request *http.Request, params *Param.Params) {
+		writer.Header().Set("Allow", allow)
+		instance.NotAllowedHandler(writer, request, params)
+	}
 }
 
 // InsertUniqueTypeChild inserts unique type child nodes (WildCard/CatchAll).
@@ -151,15 +222,21 @@ func (instance *Tree) InsertChild(parent *Node, path string) (*Node, error) {
 			Error.NewErrorWithCode(Error.DuplicateCatchAll, path) ,
 			func(parent, child *Node) { parent.CatchAll = child })
 	default:
-		child := NewNode(StaticType, path)
-		insertLocation := sort.Search(len(parent.Indices), func(index int) bool { return parent.Indices[index] >= path[0] })
-
-		parent.Indices = append(parent.Indices[:insertLocation],append([]byte{path[0]},parent.Indices[insertLocation:]...)...)
-		parent.Children = append(parent.Children[:insertLocation],append([]*Node{child},parent.Children[insertLocation:]...)...)
-		return child, nil
+		return instance.InsertStaticChild(parent, path), nil
 	}
 }
 
+// InsertStaticChild inserts a static child node into parent node, keeping Indices sorted.
+// Used directly for the rest of a partially matched segment, where ':' and '*' are plain characters.
+func (instance *Tree) InsertStaticChild(parent *Node, path string) *Node {
+	child := NewNode(StaticType, path)
+	insertLocation := sort.Search(len(parent.Indices), func(index int) bool { return parent.Indices[index] >= path[0] })
+
+	parent.Indices = append(parent.Indices[:insertLocation],append([]byte{path[0]},parent.Indices[insertLocation:]...)...)
+	parent.Children = append(parent.Children[:insertLocation],append([]*Node{child},parent.Children[insertLocation:]...)...)
+	return child
+}
+
 // SplitNode splits existing node into two nodes at split point.
 // Creates new parent node with common prefix and makes existing node a child.
 //
@@ -174,12 +251,15 @@ func (instance *Tree) SplitNode(parent *Node, child *Node, splitPoint int) (*Nod
 		return nil, Error.NewErrorWithCode(Error.SplitFailed, child.Path)
 	}
 	newParent := NewNode(StaticType, left)
+	// The common prefix takes over the child's position in the path, the remainder continues its segment
+	newParent.Partial = child.Partial
 	for index, target := range parent.Children {
 		if target == child {
 			parent.Children[index] = newParent
 			if len(right) > 0 {
 				newParent.Indices = []byte{right[0]}
 				child.Path = right
+				child.Partial = true
 				newParent.Children = []*Node{child}
 			} 
 			break
@@ -188,15 +268,84 @@ func (instance *Tree) SplitNode(parent *Node, child *Node, splitPoint int) (*Nod
 	return newParent, nil
 }
 
+// ConflictError creates a route conflict error naming both the new and the existing pattern.
+// The error is always a ConflictingRoute naming the existing pattern; for parameter name
+// clashes code is DuplicateWildCard or DuplicateCatchAll and is wrapped as the cause,
+// so errors.Is matches both.
+func (instance *Tree) ConflictError(code Error.ErrorCode, pattern string, existing string) error {
+	err := &Error.LiteFrameError{
+		Code:    Error.ConflictingRoute,
+		Message: Error.GetErrorMessage(Error.ConflictingRoute) + ": " + existing,
+		Path:    pattern,
+	}
+	if code != Error.ConflictingRoute {
+		err.Cause = Error.NewErrorWithCode(code, pattern)
+	}
+	return err
+}
+
+// ValidatePattern checks a route pattern before it touches the tree.
+//
+// Rules:
+// 1. Pattern length and segment count must not exceed MaxPathLength / MaxSegments
+// 2. ':' and '*' start a parameter only at the start of a segment, elsewhere they are literal (/v1:batch)
+// 3. Parameter names must not be empty (NilParameter)
+// 4. CatchAll must be the final segment
+// 5. Parameter names must be unique within a pattern (ConflictingRoute)
+//
+// Errors carry the full pattern as their path.
+func (instance *Tree) ValidatePattern(rawPath string) error {
+	if instance.MaxPathLength > 0 && len(rawPath) > instance.MaxPathLength {
+		return Error.NewErrorWithCode(Error.PathTooLong, rawPath)
+	}
+	path := NewPathWithSegment(rawPath)
+	segments := 0
+	var names []string
+	for path.Next(); !path.IsSame(); path.Next() {
+		segments++
+		if instance.MaxSegments > 0 && segments > instance.MaxSegments {
+			return Error.NewError(Error.PathTooLong, "Path exceeds maximum segment count", rawPath)
+		}
+		segment := path.Path[path.Start:path.End]
+		if !instance.IsWildCard(segment) && !instance.IsCatchAll(segment) {
+			continue
+		}
+		if len(segment) == 1 {
+			return Error.NewErrorWithCode(Error.NilParameter, rawPath)
+		}
+		if instance.IsCatchAll(segment) && path.End < path.PathLen && strings.Trim(path.Path[path.End:], "/") != "" {
+			return Error.NewError(Error.InvalidParameter, "Catch-all parameter must be the final segment", rawPath)
+		}
+		if slices.Contains(names, segment[1:]) {
+			code := Error.DuplicateWildCard
+			if instance.IsCatchAll(segment) {
+				code = Error.DuplicateCatchAll
+			}
+			return &Error.LiteFrameError{
+				Code:    Error.ConflictingRoute,
+				Message: "Duplicate parameter name " + segment[1:],
+				Path:    rawPath,
+				Cause:   Error.NewErrorWithCode(code, rawPath),
+			}
+		}
+		names = append(names, segment[1:])
+	}
+	return nil
+}
+
 // SetHandler registers handler for specified path and method in the tree.
 // Uses PathWithSegment for efficient path processing and creates nodes or splits existing nodes as needed.
 //
 // Operation:
-// 1. Analyze path segment by segment using PathWithSegment
-// 2. Traverse tree searching for matching nodes
-// 3. Complete match: Move to next segment
-// 4. Partial match: Split node then continue
-// 5. Match failure: Create new child node
+// 1. Validate pattern (limits, parameter placement)
+// 2. Analyze path segment by segment using PathWithSegment
+// 3. Traverse tree searching for matching nodes
+// 4. Complete match: Move to next segment
+// 5. Partial match: Split node then continue
+// 6. Match failure: Create new child node
+// 7. Reject the route if the final node already serves a different pattern
+//
+// Re-registering the same pattern and method overwrites the previous handler.
 //go:noinline
 func (instance *Tree) SetHandler(method MethodType, rawPath string, handler HandlerFunc) error {
 	if rawPath == "" {
@@ -208,34 +357,36 @@ func (instance *Tree) SetHandler(method MethodType, rawPath string, handler Hand
 	if method != CONNECT && handler == nil {
 		return Error.NewErrorWithCode(Error.InvalidParameter, rawPath)
 	}
+	if err := instance.ValidatePattern(rawPath); err != nil {
+		return err
+	}
+	pattern := CleanPath(rawPath)
 	// Efficient path processing using PathWithSegment structure
 	path := NewPathWithSegment(rawPath)
 	path.Next()
 	if path.IsSame() {
 		instance.RootNode.Handlers[method] = handler
+		instance.RootNode.Pattern = pattern
 		return nil
 	}
 	parent := instance.RootNode
 	setHelper:
 	for {
 		if path.IsSame() {
+			// Safety net: different patterns must never end on the same node
+			if parent.Pattern != "" && parent.Pattern != pattern {
+				return instance.ConflictError(Error.ConflictingRoute, pattern, parent.Pattern)
+			}
 			parent.Handlers[method] = handler
+			parent.Pattern = pattern
 			break
 		}
-		low , index , high := 0, 0, len(parent.Indices) -1
-		Target :=  path.Path[path.Start] 
-		for low <= high {
-			index = (low + high) >> 1 
-			if parent.Indices[index] < Target {
-				low = index + 1
-			} else {
-				high = index - 1
-			}
-		}
-		if low < len(parent.Indices)  {
-			index = low
+		// Children continuing the current segment and children starting a new one are kept apart
+		boundary := path.IsBoundary()
+		if index := parent.FindChild(path.Path[path.Start], !boundary); index >= 0 {
 			matched, matchingPoint, left := instance.Match(*path, parent.Children[index].Path)
-			if matched {
+			// A segment that is only a prefix of the node path must split the node instead of reusing it
+			if matched && matchingPoint == len(parent.Children[index].Path) {
 				path.Next()
 				parent = parent.Children[index]
 				continue setHelper
@@ -259,7 +410,30 @@ func (instance *Tree) SetHandler(method MethodType, rawPath string, handler Hand
 				continue setHelper 
 			}
 		}
-		child, err := instance.InsertChild(parent, path.Path[path.Start:path.End])
+		segment := path.Path[path.Start:path.End]
+		if !boundary {
+			// The rest of a partially matched segment is always static
+			child := instance.InsertStaticChild(parent, segment)
+			child.Partial = true
+			parent = child
+			path.Next()
+			continue setHelper
+		}
+		// WildCard and CatchAll on the same node are ambiguous: WildCard always wins, CatchAll is unreachable
+		if instance.IsWildCard(segment) && parent.CatchAll != nil {
+			return instance.ConflictError(Error.ConflictingRoute, pattern, parent.CatchAll.FirstPattern())
+		}
+		if instance.IsCatchAll(segment) && parent.WildCard != nil {
+			return instance.ConflictError(Error.ConflictingRoute, pattern, parent.WildCard.FirstPattern())
+		}
+		// Same position with a different parameter name (/users/:id vs /users/:name)
+		if instance.IsWildCard(segment) && parent.WildCard != nil && parent.WildCard.Param != segment[1:] {
+			return instance.ConflictError(Error.DuplicateWildCard, pattern, parent.WildCard.FirstPattern())
+		}
+		if instance.IsCatchAll(segment) && parent.CatchAll != nil && parent.CatchAll.Param != segment[1:] {
+			return instance.ConflictError(Error.DuplicateCatchAll, pattern, parent.CatchAll.FirstPattern())
+		}
+		child, err := instance.InsertChild(parent, segment)
 		if err != nil {
 			return err
 		}
@@ -271,63 +445,117 @@ func (instance *Tree) SetHandler(method MethodType, rawPath string, handler Hand
 }
 
 // GetHandler finds and returns handler corresponding to HTTP request from tree.
-// Uses PathWithSegment for efficient path processing and extracts parameters to select handler.
+// Uses Search for path matching and SelectHandler for method selection.
+// Stores the matched route pattern in request.Pattern (like http.ServeMux) so handlers
+// and middleware can label requests by route template instead of raw URL. When no route
+// matches, request.Pattern is cleared so a value set by an outer mux does not leak through.
+//
+// Returns: (handler function, parameter object) - returns nil if no parameters
+//go:noinline
+func (instance *Tree) GetHandler(request *http.Request, getParams func() *Param.Params) (HandlerFunc, *Param.Params) {
+	method := instance.StringToMethodType(request.Method)
+	node, params := instance.Search(request.URL.Path, getParams)
+	if method == NotAllowed {
+		// Unsupported methods are answered with 405 and the methods the path does support
+		request.Pattern = ""
+		return instance.Fallback(request.URL.Path, instance.NotAllowed(node)), params
+	}
+	if node == nil {
+		// Return parameter object and 404 handler when no matching route found
+		request.Pattern = ""
+		return instance.Fallback(request.URL.Path, instance.NotFoundHandler), params
+	}
+	request.Pattern = node.Pattern
+	handler := instance.SelectHandler(node, method)
+	if node.Handlers[method] == nil {
+		// 404 or 405 from a node without a handler for method
+		handler = instance.Fallback(request.URL.Path, handler)
+	}
+	return handler, params
+}
+
+// Fallback wraps a 404 or 405 handler for rawPath in the middleware of the group the path
+// belongs to (see Tree.GroupFor), so group middleware also sees requests that match no route.
+func (instance *Tree) Fallback(rawPath string, handler HandlerFunc) HandlerFunc {
+	if len(instance.groups) == 0 {
+		return handler
+	}
+	if group := instance.GroupFor(rawPath); group != nil {
+		return group.Wrap(handler)
+	}
+	return handler
+}
+
+// Search finds the node matching rawPath and extracts parameters on the way.
+// Uses PathWithSegment for efficient path processing.
 //
 // Matching priority:
 // 1. Static nodes: Exact string matching (highest priority)
 // 2. WildCard nodes: Single segment parameters (:param)
 // 3. CatchAll nodes: All remaining paths (*path, lowest priority)
 //
-// Returns: (handler function, parameter object) - returns nil if no parameters
+// Returns: (matched node or nil if no route matches, parameter object or nil if no parameters)
 //go:noinline
-func (instance *Tree) GetHandler(request *http.Request, getParams func() *Param.Params) (HandlerFunc, *Param.Params) {
-	rawPath := request.URL.Path
-	method := instance.StringToMethodType(request.Method)
+func (instance *Tree) Search(rawPath string, getParams func() *Param.Params) (*Node, *Param.Params) {
+	return instance.TraceSearch(rawPath, getParams, nil)
+}
+
+// TraceSearch is Search reporting every candidate it examines to trace (nil: no tracing).
+// Explain is built on it, so the explanation always shows the decision the router makes.
+func (instance *Tree) TraceSearch(rawPath string, getParams func() *Param.Params, trace SearchTraceFunc) (*Node, *Param.Params) {
 	var params *Param.Params
 	// Memory-efficient path processing using PathWithSegment
- 	path := NewPathWithSegment(rawPath)
-	if method == NotAllowed {
-		return instance.NotAllowedHandler, nil
-	}
+	path := NewPathWithSegment(rawPath)
 	if path.Path == "/" || path.Path == "" {
-		return instance.SelectHandler(instance.RootNode,method) , params
+		return instance.RootNode, params
 	}
 	path.Next()
 	parent := instance.RootNode
-getHelper:
+searchHelper:
 	for {
 		if path.GetLength() == 0 {
-			return instance.SelectHandler(parent, method), params
-		}
-		low , mid , high := 0, 0, len(parent.Indices) -1
-		Target := path.Path[path.Start]
-		for low <= high {
-			mid = (low + high) >> 1 
-			if parent.Indices[mid] < Target {
-				low = mid + 1
-			} else {
-				high = mid -1 
-			}
+			return parent, params
 		}
-		if low < len(parent.Indices)  {
-			childNode := parent.Children[low]
+		segment := path.Path[path.Start:path.End]
+		// A partially matched segment continues in Partial children only, a new segment never does
+		boundary := path.IsBoundary()
+		if index := parent.FindChild(path.Path[path.Start], !boundary); index >= 0 {
+			childNode := parent.Children[index]
 			matched, matchingPoint, left := instance.Match(*path, childNode.Path)
 		
-			if matched {
+			if matched && matchingPoint == len(childNode.Path) {
+				if trace != nil {
+					trace(StaticType, childNode, segment, StepMatched)
+				}
 				path.Next()
 				parent = childNode
-				continue getHelper
+				continue searchHelper
 			}
 
 			if matchingPoint > 0 && matchingPoint == len(childNode.Path) && path.GetLength() > 0 {
+				if trace != nil {
+					trace(StaticType, childNode, segment, StepPartial)
+				}
 				parent = childNode
 				path = &left
-				continue getHelper
+				continue searchHelper
 			}
+			if trace != nil {
+				trace(StaticType, childNode, segment, StepMismatch)
+			}
+		} else if trace != nil {
+			trace(StaticType, nil, segment, StepAbsent)
+		}
+		// Parameters always start a segment
+		if !boundary {
+			return nil, params
 		}
 
 		// 2nd priority: WildCard node matching (single segment capture)
 		if parent.WildCard != nil {
+			if trace != nil {
+				trace(WildCardType, parent.WildCard, segment, StepMatched)
+			}
 			if params == nil {
 				params = getParams()
 				params.Path = path.Path
@@ -336,10 +564,16 @@ getHelper:
 			params.Add(parent.WildCard.Param, path.Start,path.End)
 			path.Next()
 			parent = parent.WildCard
-			continue getHelper
+			continue searchHelper
+		}
+		if trace != nil {
+			trace(WildCardType, nil, segment, StepAbsent)
 		}
 		// 3rd priority: CatchAll node matching (capture all remaining paths)
 		if parent.CatchAll != nil {
+			if trace != nil {
+				trace(CatchAllType, parent.CatchAll, path.Path[path.Start:], StepMatched)
+			}
 			if params == nil {
 				params = getParams()
 				params.Path = path.Path
@@ -349,10 +583,12 @@ getHelper:
 			path.Start = path.End
 			// Search handler in CatchAll node with empty path (path consumption complete)
 			parent = parent.CatchAll
-			continue getHelper
+			continue searchHelper
 		}
-		// Return parameter object and 404 handler when no matching route found
-		return instance.NotFoundHandler, params
+		if trace != nil {
+			trace(CatchAllType, nil, path.Path[path.Start:], StepAbsent)
+		}
+		return nil, params
 	}
 }
 
@@ -362,16 +598,23 @@ func (instance *Tree) SetMiddleware(middleware Middleware.Middleware) {
 	instance.Middlewares = append(instance.Middlewares, middleware)
 }
 
+// CompileMiddlewares builds the middleware chain applied by ServeHTTP.
+// GetHandler is called once per middleware here rather than on every request, so state
+// a middleware prepares in GetHandler (pools, compiled configuration) is reused.
 func (instance *Tree) CompileMiddlewares() {
 	if len(instance.Middlewares) == 0 {
 		instance.CompiledMiddleware = nil
 		return
 	}
 
+	functions := make([]Middleware.MiddleWareFunc, len(instance.Middlewares))
+	for index, middleware := range instance.Middlewares {
+		functions[index] = middleware.GetHandler()
+	}
 	instance.CompiledMiddleware = func(baseHandler HandlerFunc) HandlerFunc {
 		temp := baseHandler
-		for index := len(instance.Middlewares) - 1; index > -1; index-- {
-			temp = instance.Middlewares[index].GetHandler()(temp)
+		for index := len(functions) - 1; index > -1; index-- {
+			temp = functions[index](temp)
 		}
 		return temp
 	}
@@ -388,14 +631,18 @@ func (instance *Tree) ApplyMiddleware(handler HandlerFunc) HandlerFunc {
 
 // ServeHTTP implements http.Handler interface.
 // Finds handler for request, applies middleware, then executes.
+// The parameter object is returned to the pool even if the handler panics.
 //go:noinline
 func (instance *Tree) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
 	handler, params := instance.GetHandler(request, instance.Pool.Get)
-	handler = instance.ApplyMiddleware(handler)
-	handler(writer, request, params)
-
-	// Return parameter object to pool
 	if params != nil {
-		instance.Pool.Put(params)
+		// Return parameter object to pool
+		defer instance.Pool.Put(params)
+	}
+	if instance.ErrorHandler != nil {
+		// Lets middleware reject requests through the same handler, see Middleware.HandleError
+		request = request.WithContext(Middleware.WithErrorHandler(request.Context(), instance.ErrorHandler))
 	}
+	handler = instance.ApplyMiddleware(handler)
+	handler(writer, request, params)
 }
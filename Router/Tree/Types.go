@@ -3,6 +3,7 @@
 package Tree
 
 import (
+	"LiteFrame/Router/Param"
 	"LiteFrame/Router/Types"
 )
 
@@ -10,6 +11,10 @@ import (
 // Reuses types defined in separate packages to prevent circular references.
 type HandlerFunc = Types.HandlerFunc
 
+// HandlerFuncWithError and ErrorHandlerFunc are imported from the Types package.
+type HandlerFuncWithError = Types.HandlerFuncWithError
+type ErrorHandlerFunc = Types.ErrorHandlerFunc
+
 // NodeType is an enumeration representing the type of tree node.
 // Each node can have only one type, and routing behavior is determined by the type.
 type NodeType uint32
@@ -34,6 +39,12 @@ const (
 	PathSeparator  = '/' // Path separator
 )
 
+// Route validation limits: Defaults applied by NewTree (0 disables a limit).
+const (
+	DefaultMaxPathLength = 2048 // Maximum length of a registered route pattern in bytes
+	DefaultMaxSegments   = 64   // Maximum number of segments in a registered route pattern
+)
+
 // HTTP method constants: Define HTTP methods following RFC 7231 standard.
 // Each method is used as array index to provide O(1) handler access.
 const (
@@ -48,3 +59,56 @@ const (
 	PATCH             // PATCH method - Partial resource modification (RFC 5789)
 	NotAllowed        // Unsupported method (for 405 Method Not Allowed response)
 )
+
+// Route describes a single registered route reconstructed from the tree.
+// Pattern contains parameter segments in their registration form (":param", "*path").
+type Route struct {
+	Method  string      // HTTP method string (GET, POST, etc.)
+	Pattern string      // Full route pattern (/users/:id)
+	Handler HandlerFunc // Registered handler
+}
+
+// DumpFormat is an enumeration of output formats supported by Tree.Dump.
+type DumpFormat uint32
+
+// DumpFormat constants: Output formats for tree visualization.
+const (
+	DumpText DumpFormat = iota // Indented plain text (one node per line)
+	DumpDOT                    // Graphviz DOT digraph
+	DumpJSON                   // Nested JSON document
+)
+
+// MatchStatus is an enumeration describing the outcome of resolving a path in the tree.
+type MatchStatus uint32
+
+// MatchStatus constants: Final routing decisions.
+const (
+	MatchFound            MatchStatus = iota // Handler found for method and path
+	MatchNotFound                            // No route matches the path (404)
+	MatchMethodNotAllowed                    // Route exists but not for this method (405)
+)
+
+// Step result constants: Outcome of a single candidate examined by Tree.Explain.
+const (
+	StepMatched  = "matched"  // Candidate consumed the whole segment (or remaining path for CatchAll)
+	StepPartial  = "partial"  // Static candidate consumed a prefix of the segment, rest continues below
+	StepMismatch = "mismatch" // Static candidate exists but does not match the segment
+	StepAbsent   = "absent"   // No candidate of this type exists on the node
+)
+
+// SearchTraceFunc observes every candidate Tree.TraceSearch examines, in order.
+// node is nil when no candidate of kind exists, segment is the input it was matched against
+// and result is one of the Step result constants.
+type SearchTraceFunc func(kind NodeType, node *Node, segment string, result string)
+
+// WalkFunc is the callback type used by Tree.Walk.
+// Returning a non-nil error stops the walk and the error is returned from Walk.
+type WalkFunc func(method, pattern string, handler HandlerFunc) error
+
+// LookupResult is the outcome of resolving a method and path without an HTTP request.
+type LookupResult struct {
+	Handler HandlerFunc   // Handler that would serve the request (NotFound/NotAllowed handler on failure)
+	Params  *Param.Params // Captured parameters (nil if none)
+	Pattern string        // Matched route pattern (empty if no route matched)
+	Status  MatchStatus   // MatchFound, MatchNotFound or MatchMethodNotAllowed
+}
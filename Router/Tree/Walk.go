@@ -0,0 +1,65 @@
+// Package Tree provides route table introspection over the Radix Tree.
+// Reconstructs full route patterns from compressed nodes for logging, documentation and tests.
+package Tree
+
+// Walk visits every registered route in the tree and calls walkFn for each method handler.
+// Routes are visited depth-first in matching priority order (Static, WildCard, CatchAll),
+// and methods of the same node in MethodType order, so the output is deterministic.
+// Stops and returns the first error returned by walkFn.
+func (instance *Tree) Walk(walkFn WalkFunc) error {
+	return instance.WalkNode(instance.RootNode, "/", walkFn)
+}
+
+// WalkNode walks the subtree starting at node whose full pattern is pattern.
+// Used by Walk and available for inspecting a part of the tree.
+func (instance *Tree) WalkNode(node *Node, pattern string, walkFn WalkFunc) error {
+	for method, handler := range node.Handlers {
+		if handler == nil {
+			continue
+		}
+		if err := walkFn(instance.MethodTypeToString(MethodType(method)), pattern, handler); err != nil {
+			return err
+		}
+	}
+	// 1st priority: Static children (already sorted by Indices)
+	for _, child := range node.Children {
+		if err := instance.WalkNode(child, instance.JoinPattern(pattern, child), walkFn); err != nil {
+			return err
+		}
+	}
+	// 2nd priority: WildCard child
+	if node.WildCard != nil {
+		if err := instance.WalkNode(node.WildCard, instance.JoinPattern(pattern, node.WildCard), walkFn); err != nil {
+			return err
+		}
+	}
+	// 3rd priority: CatchAll child
+	if node.CatchAll != nil {
+		if err := instance.WalkNode(node.CatchAll, instance.JoinPattern(pattern, node.CatchAll), walkFn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// JoinPattern appends child's path to the parent pattern.
+// Partial nodes continue the current segment, all other nodes start a new segment.
+//
+//go:inline
+func (instance *Tree) JoinPattern(pattern string, child *Node) string {
+	if child.Partial || (len(pattern) > 0 && pattern[len(pattern)-1] == PathSeparator) {
+		return pattern + child.Path
+	}
+	return pattern + string(PathSeparator) + child.Path
+}
+
+// Routes returns all registered routes in Walk order.
+// Convenient for startup logging, documentation generation and assertions in tests.
+func (instance *Tree) Routes() []Route {
+	routes := make([]Route, 0)
+	_ = instance.Walk(func(method, pattern string, handler HandlerFunc) error {
+		routes = append(routes, Route{Method: method, Pattern: pattern, Handler: handler})
+		return nil
+	})
+	return routes
+}
@@ -0,0 +1,114 @@
+package Tree
+
+import (
+	"LiteFrame/Router/Error"
+	"errors"
+	"testing"
+)
+
+// ======================
+// Batch Registration Tests
+// ======================
+
+func TestRegisterAll(t *testing.T) {
+	handler := CreateTestHandler()
+
+	t.Run("registers_all_routes", func(t *testing.T) {
+		tree := SetupTree()
+		err := tree.RegisterAll([]Route{
+			{Method: "GET", Pattern: "/users", Handler: handler},
+			{Method: "GET", Pattern: "/users/:id", Handler: handler},
+			{Method: "POST", Pattern: "/users", Handler: handler},
+		})
+		AssertNoError(t, err, "RegisterAll")
+
+		if routes := tree.Routes(); len(routes) != 3 {
+			t.Errorf("Expected 3 routes, got %v", routes)
+		}
+		if result := tree.Lookup("GET", "/users/1"); result.Status != MatchFound {
+			t.Errorf("Expected /users/1 to be found, got %s", result.Status)
+		}
+	})
+
+	t.Run("reports_all_failures_and_rolls_back", func(t *testing.T) {
+		tree := SetupTree()
+		AssertNoError(t, tree.SetHandler(GET, "/existing", handler), "SetHandler")
+
+		err := tree.RegisterAll([]Route{
+			{Method: "GET", Pattern: "/ok", Handler: handler},
+			{Method: "BREW", Pattern: "/coffee", Handler: handler},
+			{Method: "GET", Pattern: "/files/*path/edit", Handler: handler},
+			{Method: "GET", Pattern: "/ok/:id", Handler: handler},
+			{Method: "GET", Pattern: "/ok/*rest", Handler: handler},
+		})
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+
+		if !errors.Is(err, Error.ErrConflictingRoute) || !errors.Is(err, Error.ErrMethodNotAllowed) {
+			t.Errorf("Expected joined error to match its codes through errors.Is, got %v", err)
+		}
+
+		joined, ok := err.(interface{ Unwrap() []error })
+		if !ok {
+			t.Fatalf("Expected joined error, got %T", err)
+		}
+		expected := []struct {
+			index int
+			code  Error.ErrorCode
+		}{
+			{1, Error.MethodNotAllowed},
+			{2, Error.InvalidParameter},
+			{4, Error.ConflictingRoute},
+		}
+		failures := joined.Unwrap()
+		if len(failures) != len(expected) {
+			t.Fatalf("Expected %d failures, got %d: %v", len(expected), len(failures), err)
+		}
+		for position, failure := range failures {
+			var routeErr *RouteError
+			if !errors.As(failure, &routeErr) || routeErr.Index != expected[position].index {
+				t.Errorf("Failure %d: expected RouteError for index %d, got %v", position, expected[position].index, failure)
+				continue
+			}
+			var liteErr *Error.LiteFrameError
+			if !errors.As(failure, &liteErr) || liteErr.Code != expected[position].code {
+				t.Errorf("Failure %d: expected code %d, got %v", position, expected[position].code, failure)
+			}
+		}
+
+		routes := tree.Routes()
+		if len(routes) != 1 || routes[0].Pattern != "/existing" {
+			t.Errorf("Expected tree to be unchanged, got %v", routes)
+		}
+	})
+
+	t.Run("batch_builder", func(t *testing.T) {
+		tree := SetupTree()
+		err := tree.NewBatch().
+			Add("GET", "/a", handler).
+			Add("GET", "/b", handler).
+			Commit()
+		AssertNoError(t, err, "Commit")
+		if routes := tree.Routes(); len(routes) != 2 {
+			t.Errorf("Expected 2 routes, got %v", routes)
+		}
+	})
+}
+
+func TestCloneNode(t *testing.T) {
+	tree, err := SetupTreeWithRoutes([]RouteConfig{
+		{"GET", "/users/:id", CreateTestHandler()},
+		{"GET", "/files/*path", CreateTestHandler()},
+	})
+	AssertNoError(t, err, "SetupTreeWithRoutes")
+
+	clone := tree.CloneNode(tree.RootNode)
+	AssertNoError(t, tree.SetHandler(GET, "/users/:id/posts", CreateTestHandler()), "SetHandler")
+
+	cloneTree := SetupTree()
+	cloneTree.RootNode = clone
+	if len(cloneTree.Routes()) != 2 {
+		t.Errorf("Expected clone to be unaffected by later registrations, got %v", cloneTree.Routes())
+	}
+}
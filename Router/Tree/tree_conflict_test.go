@@ -0,0 +1,193 @@
+package Tree
+
+import (
+	"LiteFrame/Router/Error"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// ======================
+// Route Conflict Detection Tests
+// ======================
+
+// assertErrorCode validates that err is a LiteFrameError with the expected code
+func assertErrorCode(t *testing.T, err error, expected Error.ErrorCode) *Error.LiteFrameError {
+	t.Helper()
+	liteErr, ok := err.(*Error.LiteFrameError)
+	if !ok {
+		t.Fatalf("Expected *LiteFrameError with code %d, got %v", expected, err)
+	}
+	if liteErr.Code != expected {
+		t.Errorf("Expected code %d, got %d (%v)", expected, liteErr.Code, err)
+	}
+	return liteErr
+}
+
+func TestSetHandler_Conflicts(t *testing.T) {
+	handler := CreateTestHandler()
+
+	tests := []struct {
+		name     string
+		existing string
+		pattern  string
+		code     Error.ErrorCode
+	}{
+		{"catch_all_after_wildcard", "/files/:name", "/files/*path", Error.ConflictingRoute},
+		{"wildcard_after_catch_all", "/files/*path", "/files/:name", Error.ConflictingRoute},
+		{"wildcard_name", "/users/:id/posts", "/users/:name", Error.DuplicateWildCard},
+		{"catch_all_name", "/static/*file", "/static/*path", Error.DuplicateCatchAll},
+	}
+
+	// Every conflict is a ConflictingRoute; name clashes keep their specific code as the cause
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tree := SetupTree()
+			AssertNoError(t, tree.SetHandler(GET, test.existing, handler), "SetHandler "+test.existing)
+
+			err := tree.SetHandler(GET, test.pattern, handler)
+			if !errors.Is(err, &Error.LiteFrameError{Code: test.code}) {
+				t.Errorf("Expected errors.Is to match code %d, got %v", test.code, err)
+			}
+			liteErr := assertErrorCode(t, err, Error.ConflictingRoute)
+			if liteErr.Path != test.pattern {
+				t.Errorf("Expected new pattern %q in error, got %q", test.pattern, liteErr.Path)
+			}
+			if !strings.Contains(liteErr.Message, test.existing) {
+				t.Errorf("Expected existing pattern %q in message, got %q", test.existing, liteErr.Message)
+			}
+		})
+	}
+
+	t.Run("same_pattern_overwrites", func(t *testing.T) {
+		tree := SetupTree()
+		AssertNoError(t, tree.SetHandler(GET, "/users/:id", handler), "first")
+		AssertNoError(t, tree.SetHandler(GET, "/users/:id/", handler), "trailing slash variant")
+		AssertNoError(t, tree.SetHandler(POST, "/users/:id", handler), "other method")
+	})
+
+	t.Run("static_and_wildcard_coexist", func(t *testing.T) {
+		tree := SetupTree()
+		AssertNoError(t, tree.SetHandler(GET, "/users/:id", handler), "wildcard")
+		AssertNoError(t, tree.SetHandler(GET, "/users/admin", handler), "static")
+	})
+}
+
+func TestSetHandler_SegmentBoundaries(t *testing.T) {
+	for _, order := range [][]string{{"/users", "/user/s/x"}, {"/user/s/x", "/users"}} {
+		t.Run("registered_"+strings.Join(order, "_then_"), func(t *testing.T) {
+			tree := SetupTree()
+			for _, pattern := range order {
+				AssertNoError(t, tree.SetHandler(GET, pattern, CreateHandlerWithResponse(pattern)), "SetHandler "+pattern)
+			}
+
+			patterns := make([]string, 0)
+			for _, route := range tree.Routes() {
+				patterns = append(patterns, route.Pattern)
+			}
+			slices.Sort(patterns)
+			if strings.Join(patterns, " ") != "/user/s/x /users" {
+				t.Errorf("Expected routes /user/s/x and /users, got %v", patterns)
+			}
+
+			AssertResponseBody(t, ExecuteRequest(tree, "GET", "/users"), "/users")
+			AssertResponseBody(t, ExecuteRequest(tree, "GET", "/user/s/x"), "/user/s/x")
+			AssertStatusCode(t, ExecuteRequest(tree, "GET", "/users/x"), http.StatusNotFound)
+			AssertStatusCode(t, ExecuteRequest(tree, "GET", "/user/s"), http.StatusNotFound)
+		})
+	}
+
+	t.Run("wildcard_not_entered_mid_segment", func(t *testing.T) {
+		tree := SetupTree()
+		AssertNoError(t, tree.SetHandler(GET, "/user/:id", CreateHandlerWithResponse("user")), "wildcard")
+		AssertNoError(t, tree.SetHandler(GET, "/users", CreateHandlerWithResponse("users")), "static")
+
+		AssertResponseBody(t, ExecuteRequest(tree, "GET", "/users"), "users")
+		AssertResponseBody(t, ExecuteRequest(tree, "GET", "/user/42"), "user")
+		AssertStatusCode(t, ExecuteRequest(tree, "GET", "/userfoo"), http.StatusNotFound)
+		AssertStatusCode(t, ExecuteRequest(tree, "GET", "/users/42"), http.StatusNotFound)
+	})
+}
+
+func TestValidatePattern(t *testing.T) {
+	tree := SetupTree()
+
+	tests := []struct {
+		name    string
+		pattern string
+		code    Error.ErrorCode
+		valid   bool
+	}{
+		{"plain", "/users/:id/files/*path", 0, true},
+		{"catch_all_trailing_slash", "/files/*path/", 0, true},
+		{"catch_all_not_last", "/files/*path/edit", Error.InvalidParameter, false},
+		{"colon_mid_segment", "/v1:batch", 0, true},
+		{"asterisk_mid_segment", "/files/a*b", 0, true},
+		{"duplicate_param_name", "/a/:id/b/:id", Error.ConflictingRoute, false},
+		{"duplicate_catch_all_name", "/:path/b/*path", Error.ConflictingRoute, false},
+		{"empty_wildcard_name", "/users/:/edit", Error.NilParameter, false},
+		{"empty_catch_all_name", "/files/*", Error.NilParameter, false},
+		{"too_long", "/" + strings.Repeat("a", DefaultMaxPathLength), Error.PathTooLong, false},
+		{"too_many_segments", strings.Repeat("/a", DefaultMaxSegments+1), Error.PathTooLong, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := tree.ValidatePattern(test.pattern)
+			if test.valid {
+				AssertNoError(t, err, "ValidatePattern")
+				return
+			}
+			liteErr := assertErrorCode(t, err, test.code)
+			if liteErr.Path != test.pattern {
+				t.Errorf("Expected full pattern %q in error, got %q", test.pattern, liteErr.Path)
+			}
+		})
+	}
+
+	t.Run("duplicate_name_through_set_handler", func(t *testing.T) {
+		tree := SetupTree()
+		err := tree.SetHandler(GET, "/:id/x/:id", CreateTestHandler())
+		assertErrorCode(t, err, Error.ConflictingRoute)
+		if !errors.Is(err, Error.ErrDuplicateWildCard) {
+			t.Errorf("Expected DuplicateWildCard cause, got %v", err)
+		}
+	})
+
+	t.Run("limits_configurable", func(t *testing.T) {
+		tree := SetupTree()
+		tree.MaxPathLength = 8
+		tree.MaxSegments = 2
+		assertErrorCode(t, tree.SetHandler(GET, "/a/b/c", CreateTestHandler()), Error.PathTooLong)
+		assertErrorCode(t, tree.SetHandler(GET, "/abcdefgh", CreateTestHandler()), Error.PathTooLong)
+		AssertNoError(t, tree.SetHandler(GET, "/a/b", CreateTestHandler()), "within limits")
+
+		tree.MaxPathLength = 0
+		tree.MaxSegments = 0
+		AssertNoError(t, tree.SetHandler(GET, strings.Repeat("/a", DefaultMaxSegments+1), CreateTestHandler()), "unlimited")
+	})
+}
+
+func TestSetHandler_LiteralParameterPrefixes(t *testing.T) {
+	tree, err := SetupTreeWithRoutes([]RouteConfig{
+		{"GET", "/v1", CreateHandlerWithResponse("v1")},
+		{"POST", "/v1:batch", CreateHandlerWithResponse("batch")},
+		{"GET", "/files/:name", CreateHandlerWithResponse("name")},
+		{"GET", "/files/a*b", CreateHandlerWithResponse("literal")},
+	})
+	AssertNoError(t, err, "SetupTreeWithRoutes")
+
+	AssertResponseBody(t, ExecuteRequest(tree, "GET", "/v1"), "v1")
+	AssertResponseBody(t, ExecuteRequest(tree, "POST", "/v1:batch"), "batch")
+	AssertResponseBody(t, ExecuteRequest(tree, "GET", "/files/a*b"), "literal")
+	AssertResponseBody(t, ExecuteRequest(tree, "GET", "/files/b"), "name")
+	AssertStatusCode(t, ExecuteRequest(tree, "POST", "/v1:other"), http.StatusNotFound)
+
+	result := tree.Lookup("POST", "/v1:batch")
+	if result.Status != MatchFound || result.Params != nil || result.Pattern != "/v1:batch" {
+		t.Errorf("Expected static match for /v1:batch, got %+v", result)
+	}
+}
@@ -0,0 +1,97 @@
+package Tree
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// ======================
+// Dump Tests
+// ======================
+
+func setupDumpTree(t *testing.T) Tree {
+	handler := CreateTestHandler()
+	tree, err := SetupTreeWithRoutes([]RouteConfig{
+		{"GET", "/users", handler},
+		{"POST", "/users", handler},
+		{"GET", "/user/profile", handler},
+		{"GET", "/users/:id", handler},
+		{"GET", "/files/*path", handler},
+	})
+	AssertNoError(t, err, "SetupTreeWithRoutes")
+	return tree
+}
+
+func TestDump(t *testing.T) {
+	tree := setupDumpTree(t)
+
+	t.Run("text", func(t *testing.T) {
+		var buffer bytes.Buffer
+		AssertNoError(t, tree.Dump(&buffer, DumpText), "Dump text")
+
+		expected := strings.Join([]string{
+			`root "/" indices="fu"`,
+			`  static "files"`,
+			`    catchall "*path" param=path methods=GET`,
+			`  static "user" indices="ps"`,
+			`    static "profile" methods=GET`,
+			`    static "s" partial methods=GET,POST`,
+			`      wildcard ":id" param=id methods=GET`,
+			``,
+		}, "\n")
+		if buffer.String() != expected {
+			t.Errorf("Unexpected text dump:\n%s\nexpected:\n%s", buffer.String(), expected)
+		}
+	})
+
+	t.Run("dot", func(t *testing.T) {
+		var buffer bytes.Buffer
+		AssertNoError(t, tree.Dump(&buffer, DumpDOT), "Dump DOT")
+
+		output := buffer.String()
+		for _, fragment := range []string{
+			"digraph Tree {",
+			`n0 [label="root\n/"];`,
+			`n0 -> n1 [label="f"];`,
+			`n1 -> n2 [label="*", style=dotted];`,
+			`n5 [label="static\ns (partial)\nGET,POST", style=bold];`,
+			`n5 -> n6 [label=":", style=dashed];`,
+		} {
+			if !strings.Contains(output, fragment) {
+				t.Errorf("Expected DOT output to contain %q, got:\n%s", fragment, output)
+			}
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var buffer bytes.Buffer
+		AssertNoError(t, tree.Dump(&buffer, DumpJSON), "Dump JSON")
+
+		var info NodeInfo
+		AssertNoError(t, json.Unmarshal(buffer.Bytes(), &info), "Unmarshal")
+
+		if info.Type != "root" || len(info.Children) != 2 {
+			t.Fatalf("Unexpected root info: %+v", info)
+		}
+		users := info.Children[1].Children[1]
+		if users.Path != "s" || !users.Partial || strings.Join(users.Methods, ",") != "GET,POST" {
+			t.Errorf("Unexpected users node: %+v", users)
+		}
+		if users.WildCard == nil || users.WildCard.Param != "id" {
+			t.Errorf("Expected wildcard child with param id, got %+v", users.WildCard)
+		}
+	})
+
+	t.Run("unknown_format", func(t *testing.T) {
+		var buffer bytes.Buffer
+		AssertError(t, tree.Dump(&buffer, DumpFormat(99)), "unknown format")
+	})
+}
+
+func TestDOTEscape(t *testing.T) {
+	if result := DOTEscape(`a"b\c`); result != `a\"b\\c` {
+		t.Errorf("Unexpected escape result %q", result)
+	}
+}
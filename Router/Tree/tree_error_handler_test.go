@@ -0,0 +1,129 @@
+package Tree
+
+import (
+	"LiteFrame/Router/Error"
+	"LiteFrame/Router/Middleware"
+	"LiteFrame/Router/Param"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// ======================
+// Error-Returning Handler Tests
+// ======================
+
+func TestSetHandlerWithError(t *testing.T) {
+	tree := SetupTree()
+
+	err := tree.SetHandlerWithError(GET, "/users/:id", func(w http.ResponseWriter, r *http.Request, params *Param.Params) error {
+		id, err := params.Require("id")
+		if err != nil {
+			return err
+		}
+		if id == "0" {
+			return errors.New("database unavailable")
+		}
+		_, _ = w.Write([]byte("user " + id))
+		return nil
+	})
+	AssertNoError(t, err, "SetHandlerWithError")
+
+	t.Run("success", func(t *testing.T) {
+		recorder := ExecuteRequest(tree, "GET", "/users/7")
+		AssertStatusCode(t, recorder, http.StatusOK)
+		AssertResponseBody(t, recorder, "user 7")
+	})
+
+	t.Run("default_error_handler", func(t *testing.T) {
+		recorder := ExecuteRequest(tree, "GET", "/users/0")
+		AssertStatusCode(t, recorder, http.StatusInternalServerError)
+		if contentType := recorder.Header().Get("Content-Type"); contentType != Error.ProblemContentType {
+			t.Errorf("Expected problem+json content type, got %q", contentType)
+		}
+	})
+
+	t.Run("custom_error_handler", func(t *testing.T) {
+		var received error
+		tree.ErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
+			received = err
+			w.WriteHeader(http.StatusTeapot)
+		}
+		defer func() { tree.ErrorHandler = Error.WriteError }()
+
+		recorder := ExecuteRequest(tree, "GET", "/users/0")
+		AssertStatusCode(t, recorder, http.StatusTeapot)
+		if received == nil || received.Error() != "database unavailable" {
+			t.Errorf("Expected handler error to reach ErrorHandler, got %v", received)
+		}
+	})
+
+	t.Run("nil_handler", func(t *testing.T) {
+		err := tree.SetHandlerWithError(GET, "/nil", nil)
+		if !errors.Is(err, Error.ErrInvalidHandler) {
+			t.Errorf("Expected ErrInvalidHandler, got %v", err)
+		}
+	})
+}
+
+func TestHandleError(t *testing.T) {
+	tree := SetupTree()
+	tree.ErrorHandler = nil
+
+	handler := tree.Adapt(func(w http.ResponseWriter, r *http.Request, params *Param.Params) error {
+		return Error.NewErrorWithCode(Error.ParameterMissing, r.URL.Path)
+	})
+	AssertNoError(t, tree.SetHandler(GET, "/search", handler), "SetHandler")
+
+	recorder := ExecuteRequest(tree, "GET", "/search")
+	AssertStatusCode(t, recorder, http.StatusBadRequest)
+}
+
+func TestErrorHandler_MiddlewareRejections(t *testing.T) {
+	tree := SetupTree()
+	var received error
+	tree.ErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
+		received = err
+		w.WriteHeader(http.StatusTeapot)
+	}
+	tree.SetMiddleware(Middleware.NewBodyLimit(4))
+	tree.CompileMiddlewares()
+	AssertNoError(t, tree.SetHandler(POST, "/upload", func(w http.ResponseWriter, r *http.Request, params *Param.Params) {
+		_, _ = io.ReadAll(r.Body)
+	}), "SetHandler")
+
+	request := httptest.NewRequest("POST", "/upload", strings.NewReader("too large"))
+	recorder := httptest.NewRecorder()
+	tree.ServeHTTP(recorder, request)
+	AssertStatusCode(t, recorder, http.StatusTeapot)
+	if Error.Code(received) != Error.RequestTooLarge {
+		t.Errorf("Expected the middleware error to reach the tree's ErrorHandler, got %v", received)
+	}
+}
+
+func TestErrorHandler_RecoveredPanics(t *testing.T) {
+	tree := SetupTree()
+	var received error
+	tree.ErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
+		received = err
+		w.WriteHeader(http.StatusTeapot)
+	}
+	recovery := Middleware.NewRecovery()
+	recovery.Hook = nil
+	tree.SetMiddleware(recovery)
+	tree.CompileMiddlewares()
+	AssertNoError(t, tree.SetHandler(GET, "/panic", func(w http.ResponseWriter, r *http.Request, params *Param.Params) {
+		panic("boom")
+	}), "SetHandler")
+
+	recorder := httptest.NewRecorder()
+	tree.ServeHTTP(recorder, httptest.NewRequest("GET", "/panic", nil))
+	AssertStatusCode(t, recorder, http.StatusTeapot)
+	var panicErr *Middleware.PanicError
+	if !errors.As(received, &panicErr) {
+		t.Errorf("Expected the PanicError to reach the tree's ErrorHandler, got %v", received)
+	}
+}
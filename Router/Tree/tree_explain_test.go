@@ -0,0 +1,148 @@
+package Tree
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// ======================
+// Explain Tests
+// ======================
+
+func setupExplainTree(t *testing.T) Tree {
+	handler := CreateTestHandler()
+	tree, err := SetupTreeWithRoutes([]RouteConfig{
+		{"GET", "/users", handler},
+		{"GET", "/users/:id", handler},
+		{"GET", "/users/admin", handler},
+		{"GET", "/files/*path", handler},
+		{"POST", "/api/v1/items", handler},
+	})
+	AssertNoError(t, err, "SetupTreeWithRoutes")
+	return tree
+}
+
+func TestExplain(t *testing.T) {
+	tree := setupExplainTree(t)
+
+	t.Run("static_over_wildcard", func(t *testing.T) {
+		explanation := tree.Explain("GET", "/users/admin")
+		if explanation.Status != MatchFound || explanation.Pattern != "/users/admin" {
+			t.Fatalf("Expected found /users/admin, got %s %s", explanation.Status, explanation.Pattern)
+		}
+		if len(explanation.Steps) != 2 || explanation.Steps[1].Result != StepMatched || explanation.Steps[1].Kind != "static" {
+			t.Errorf("Unexpected steps: %+v", explanation.Steps)
+		}
+		if len(explanation.Params) != 0 {
+			t.Errorf("Expected no params, got %+v", explanation.Params)
+		}
+	})
+
+	t.Run("wildcard_after_static_absent", func(t *testing.T) {
+		explanation := tree.Explain("GET", "/users/42")
+		if explanation.Status != MatchFound || explanation.Pattern != "/users/:id" {
+			t.Fatalf("Expected found /users/:id, got %s %s", explanation.Status, explanation.Pattern)
+		}
+		results := make([]string, 0)
+		for _, step := range explanation.Steps {
+			results = append(results, step.Kind+":"+step.Result)
+		}
+		expected := "static:matched static:absent wildcard:matched"
+		if strings.Join(results, " ") != expected {
+			t.Errorf("Expected steps %q, got %q", expected, strings.Join(results, " "))
+		}
+		if len(explanation.Params) != 1 || explanation.Params[0] != (CapturedParam{Key: "id", Value: "42"}) {
+			t.Errorf("Unexpected params: %+v", explanation.Params)
+		}
+	})
+
+	t.Run("catch_all", func(t *testing.T) {
+		explanation := tree.Explain("GET", "/files/css/main.css")
+		if explanation.Status != MatchFound || explanation.Pattern != "/files/*path" {
+			t.Fatalf("Expected found /files/*path, got %s %s", explanation.Status, explanation.Pattern)
+		}
+		last := explanation.Steps[len(explanation.Steps)-1]
+		if last.Kind != "catchall" || last.Segment != "css/main.css" {
+			t.Errorf("Unexpected last step: %+v", last)
+		}
+	})
+
+	t.Run("not_found", func(t *testing.T) {
+		explanation := tree.Explain("GET", "/unknown")
+		if explanation.Status != MatchNotFound {
+			t.Errorf("Expected not-found, got %s", explanation.Status)
+		}
+		if len(explanation.Steps) != 3 {
+			t.Errorf("Expected static, wildcard and catchall steps, got %+v", explanation.Steps)
+		}
+	})
+
+	t.Run("intermediate_node_not_found", func(t *testing.T) {
+		explanation := tree.Explain("GET", "/api/v1")
+		if explanation.Status != MatchNotFound {
+			t.Errorf("Expected not-found for node without handlers, got %s", explanation.Status)
+		}
+	})
+
+	t.Run("method_not_allowed", func(t *testing.T) {
+		explanation := tree.Explain("GET", "/api/v1/items")
+		if explanation.Status != MatchMethodNotAllowed || explanation.Pattern != "/api/v1/items" {
+			t.Errorf("Expected method-not-allowed on /api/v1/items, got %s %s", explanation.Status, explanation.Pattern)
+		}
+		explanation = tree.Explain("BREW", "/users")
+		if explanation.Status != MatchMethodNotAllowed {
+			t.Errorf("Expected method-not-allowed for unknown method, got %s", explanation.Status)
+		}
+	})
+
+	t.Run("string", func(t *testing.T) {
+		output := tree.Explain("GET", "/users/42").String()
+		if !strings.Contains(output, "=> found (/users/:id)") || !strings.Contains(output, `param id="42"`) {
+			t.Errorf("Unexpected text output:\n%s", output)
+		}
+	})
+}
+
+func TestExplain_AgreesWithLookup(t *testing.T) {
+	tree := setupExplainTree(t)
+	paths := []string{"/", "/users", "/users/42", "/users/admin", "/users/42/extra", "/files", "/files/a/b", "/api", "/api/v1/items", "/unknown"}
+	for _, path := range paths {
+		explanation := tree.Explain("GET", path)
+		result := tree.Lookup("GET", path)
+		// Explain also names intermediate nodes, Lookup only registered patterns
+		if explanation.Status != result.Status || (result.Pattern != "" && explanation.Pattern != result.Pattern) {
+			t.Errorf("%s: Explain reported %s %q, Lookup %s %q", path, explanation.Status, explanation.Pattern, result.Status, result.Pattern)
+		}
+	}
+}
+
+func TestExplainHandler(t *testing.T) {
+	tree := setupExplainTree(t)
+	handler := tree.ExplainHandler()
+
+	request := httptest.NewRequest("GET", "/debug/routes?method=GET&path=/users/42", nil)
+	recorder := httptest.NewRecorder()
+	handler(recorder, request, nil)
+
+	AssertStatusCode(t, recorder, http.StatusOK)
+	var decoded struct {
+		Status  string `json:"status"`
+		Pattern string `json:"pattern"`
+	}
+	AssertNoError(t, json.Unmarshal(recorder.Body.Bytes(), &decoded), "Unmarshal")
+	if decoded.Status != "found" || decoded.Pattern != "/users/:id" {
+		t.Errorf("Unexpected response: %s", recorder.Body.String())
+	}
+}
+
+func TestGetHandler_IntermediateNode(t *testing.T) {
+	tree := setupExplainTree(t)
+	tree.NotFoundHandler = CreateHandlerWithResponse("not found")
+	tree.NotAllowedHandler = CreateHandlerWithResponse("not allowed")
+
+	AssertResponseBody(t, ExecuteRequest(tree, "GET", "/api/v1"), "not found")
+	AssertResponseBody(t, ExecuteRequest(tree, "GET", "/api/v1/items"), "not allowed")
+}
@@ -281,3 +281,116 @@ func TestAdditionalScenarios(t *testing.T) {
 		}
 	})
 }
+
+// ======================
+// Segment Prefix Tests
+// ======================
+
+func TestGetHandler_SegmentPrefix(t *testing.T) {
+	tree := SetupTree()
+	tree.NotFoundHandler = func(w http.ResponseWriter, r *http.Request, params *Param.Params) {
+		w.WriteHeader(http.StatusNotFound)
+	}
+
+	err := tree.SetHandler(tree.StringToMethodType("GET"), "/users", CreateHandlerWithResponse("users"))
+	AssertNoError(t, err, "SetHandler /users")
+
+	t.Run("prefix_segment_not_matched", func(t *testing.T) {
+		recorder := ExecuteRequest(tree, "GET", "/u")
+		AssertStatusCode(t, recorder, http.StatusNotFound)
+	})
+
+	t.Run("prefix_segment_registered_separately", func(t *testing.T) {
+		err := tree.SetHandler(tree.StringToMethodType("GET"), "/user/profile", CreateHandlerWithResponse("profile"))
+		AssertNoError(t, err, "SetHandler /user/profile")
+
+		recorder := ExecuteRequest(tree, "GET", "/user/profile")
+		AssertResponseBody(t, recorder, "profile")
+
+		recorder = ExecuteRequest(tree, "GET", "/users")
+		AssertResponseBody(t, recorder, "users")
+
+		recorder = ExecuteRequest(tree, "GET", "/users/profile")
+		AssertStatusCode(t, recorder, http.StatusNotFound)
+	})
+}
+
+// ======================
+// Default Handler Tests
+// ======================
+
+func TestDefaultHandlers(t *testing.T) {
+	tree := SetupTree()
+	err := tree.SetHandler(tree.StringToMethodType("POST"), "/users", CreateTestHandler())
+	AssertNoError(t, err, "SetHandler")
+
+	testCases := []struct {
+		name   string
+		method string
+		path   string
+		status int
+	}{
+		{"not_found", "GET", "/missing", http.StatusNotFound},
+		{"method_not_allowed", "GET", "/users", http.StatusMethodNotAllowed},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := ExecuteRequest(tree, tc.method, tc.path)
+			AssertStatusCode(t, recorder, tc.status)
+			if contentType := recorder.Header().Get("Content-Type"); contentType != "application/problem+json" {
+				t.Errorf("Expected problem+json content type, got %q", contentType)
+			}
+		})
+	}
+}
+
+func TestNotAllowed_AllowHeader(t *testing.T) {
+	tree, err := SetupTreeWithRoutes([]RouteConfig{
+		{"POST", "/users", CreateTestHandler()},
+		{"GET", "/users", CreateTestHandler()},
+		{"DELETE", "/users/:id", CreateTestHandler()},
+	})
+	AssertNoError(t, err, "SetupTreeWithRoutes")
+
+	testCases := []struct {
+		name   string
+		method string
+		path   string
+		allow  string
+	}{
+		{"registered_methods", "PUT", "/users", "GET, POST"},
+		{"wildcard_route", "GET", "/users/7", "DELETE"},
+		{"unsupported_method", "BREW", "/users", "GET, POST"},
+		{"unsupported_method_unknown_path", "BREW", "/missing", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := ExecuteRequest(tree, tc.method, tc.path)
+			AssertStatusCode(t, recorder, http.StatusMethodNotAllowed)
+			allow, ok := recorder.Header()["Allow"]
+			if !ok || allow[0] != tc.allow {
+				t.Errorf("Expected Allow %q, got %v", tc.allow, allow)
+			}
+		})
+	}
+
+	t.Run("custom_handler", func(t *testing.T) {
+		tree.NotAllowedHandler = func(w http.ResponseWriter, r *http.Request, params *Param.Params) {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+		recorder := ExecuteRequest(tree, "PATCH", "/users")
+		if allow := recorder.Header().Get("Allow"); allow != "GET, POST" {
+			t.Errorf("Expected Allow for custom handler, got %q", allow)
+		}
+	})
+
+	t.Run("found_and_not_found_unaffected", func(t *testing.T) {
+		for _, path := range []string{"/users", "/missing"} {
+			if _, ok := ExecuteRequest(tree, "GET", path).Header()["Allow"]; ok {
+				t.Errorf("Expected no Allow header for GET %s", path)
+			}
+		}
+	})
+}
@@ -0,0 +1,283 @@
+package Tree
+
+import (
+	"LiteFrame/Router/Error"
+	"LiteFrame/Router/Middleware"
+	"LiteFrame/Router/Param"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// ======================
+// Group Tests
+// ======================
+
+// tagMiddleware appends its name to the X-Chain response header
+type tagMiddleware string
+
+func (instance tagMiddleware) GetHandler() Middleware.MiddleWareFunc {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request, params *Param.Params) {
+			w.Header().Add("X-Chain", string(instance))
+			next(w, r, params)
+		}
+	}
+}
+
+// buildCounter counts how often its middleware chain is built
+type buildCounter struct {
+	builds *int
+}
+
+func (instance buildCounter) GetHandler() Middleware.MiddleWareFunc {
+	*instance.builds++
+	return func(next HandlerFunc) HandlerFunc {
+		return next
+	}
+}
+
+func TestCompileMiddlewares_BuildsOnce(t *testing.T) {
+	tree := SetupTree()
+	AssertNoError(t, tree.SetHandler(GET, "/", CreateTestHandler()), "SetHandler")
+	builds := 0
+	tree.SetMiddleware(buildCounter{builds: &builds})
+	tree.CompileMiddlewares()
+
+	for range 3 {
+		AssertStatusCode(t, ExecuteRequest(tree, "GET", "/"), http.StatusOK)
+	}
+	if builds != 1 {
+		t.Errorf("Expected GetHandler to be called once, got %d", builds)
+	}
+}
+
+func TestGroup_BuildsMiddlewareOnce(t *testing.T) {
+	tree := SetupTree()
+	builds := 0
+	api := tree.Group("/api", buildCounter{builds: &builds})
+	admin := api.Group("/admin")
+	for _, path := range []string{"/a", "/b", "/c"} {
+		AssertNoError(t, api.SetHandler(GET, path, CreateTestHandler()), "api SetHandler "+path)
+		AssertNoError(t, admin.SetHandler(GET, path, CreateTestHandler()), "admin SetHandler "+path)
+	}
+	if builds != 1 {
+		t.Errorf("Expected the group middleware to be built once, got %d", builds)
+	}
+	api.Use(buildCounter{builds: &builds})
+	AssertNoError(t, api.SetHandler(GET, "/d", CreateTestHandler()), "api SetHandler /d")
+	if builds != 2 {
+		t.Errorf("Expected Use to build the added middleware once, got %d", builds)
+	}
+}
+
+func TestGroup(t *testing.T) {
+	tree := SetupTree()
+	handler := CreateHandlerWithResponse("ok")
+
+	api := tree.Group("/api/", tagMiddleware("api"))
+	admin := api.Group("admin", tagMiddleware("admin"))
+	AssertNoError(t, api.SetHandler(GET, "/users/:id", handler), "api SetHandler")
+	AssertNoError(t, admin.SetHandler(GET, "/stats", handler), "admin SetHandler")
+	AssertNoError(t, api.SetHandler(GET, "/", handler), "api root SetHandler")
+	api.Use(tagMiddleware("late"))
+	AssertNoError(t, api.SetHandler(GET, "/health", handler), "api health SetHandler")
+	AssertNoError(t, tree.SetHandler(GET, "/public", handler), "tree SetHandler")
+
+	tests := []struct {
+		path  string
+		chain string
+	}{
+		{"/api/users/42", "api"},
+		{"/api/admin/stats", "api,admin"},
+		{"/api", "api"},
+		{"/api/health", "api,late"},
+		{"/public", ""},
+	}
+	for _, test := range tests {
+		recorder := ExecuteRequest(tree, "GET", test.path)
+		AssertStatusCode(t, recorder, http.StatusOK)
+		if chain := strings.Join(recorder.Header().Values("X-Chain"), ","); chain != test.chain {
+			t.Errorf("%s: expected middleware chain %q, got %q", test.path, test.chain, chain)
+		}
+	}
+
+	if admin.Prefix != "/api/admin" {
+		t.Errorf("Expected nested prefix /api/admin, got %q", admin.Prefix)
+	}
+	if err := api.SetHandler(GET, "/nil", nil); !errors.Is(err, &Error.LiteFrameError{Code: Error.InvalidParameter}) {
+		t.Errorf("Expected InvalidParameter for nil handler, got %v", err)
+	}
+	AssertNoError(t, api.SetHandler(CONNECT, "/tunnel", nil), "nil CONNECT handler")
+
+	t.Run("with_error", func(t *testing.T) {
+		err := api.SetHandlerWithError(POST, "/fail", func(w http.ResponseWriter, r *http.Request, params *Param.Params) error {
+			return Error.NewErrorWithCode(Error.ParameterMissing, r.URL.Path)
+		})
+		AssertNoError(t, err, "SetHandlerWithError")
+		recorder := ExecuteRequest(tree, "POST", "/api/fail")
+		AssertStatusCode(t, recorder, http.StatusBadRequest)
+		if recorder.Header().Get("X-Chain") == "" {
+			t.Error("Expected group middleware to wrap error-returning handlers")
+		}
+	})
+}
+
+func TestGroupTimeout(t *testing.T) {
+	tree := SetupTree()
+	slow := func(w http.ResponseWriter, r *http.Request, params *Param.Params) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(50 * time.Millisecond):
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+	fast := tree.Group("/fast", Middleware.NewTimeout(5*time.Millisecond))
+	AssertNoError(t, fast.SetHandler(GET, "/slow", slow), "fast SetHandler")
+	AssertNoError(t, tree.SetHandler(GET, "/slow", slow), "tree SetHandler")
+
+	recorder := httptest.NewRecorder()
+	tree.ServeHTTP(recorder, httptest.NewRequest("GET", "/fast/slow", nil))
+	AssertStatusCode(t, recorder, http.StatusServiceUnavailable)
+
+	recorder = httptest.NewRecorder()
+	tree.ServeHTTP(recorder, httptest.NewRequest("GET", "/slow", nil))
+	AssertStatusCode(t, recorder, http.StatusOK)
+}
+
+func TestGroupBodyLimit(t *testing.T) {
+	tree := SetupTree()
+	api := tree.Group("/api", Middleware.NewBodyLimit(8))
+	echo := func(w http.ResponseWriter, r *http.Request, params *Param.Params) error {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(body)
+		return err
+	}
+	AssertNoError(t, api.SetHandlerWithError(POST, "/echo", echo), "api SetHandlerWithError")
+	AssertNoError(t, tree.SetHandlerWithError(POST, "/echo", echo), "tree SetHandlerWithError")
+
+	request := httptest.NewRequest("POST", "/api/echo", strings.NewReader(strings.Repeat("x", 32)))
+	request.ContentLength = -1
+	recorder := httptest.NewRecorder()
+	tree.ServeHTTP(recorder, request)
+	AssertStatusCode(t, recorder, http.StatusRequestEntityTooLarge)
+
+	recorder = httptest.NewRecorder()
+	tree.ServeHTTP(recorder, httptest.NewRequest("POST", "/echo", strings.NewReader(strings.Repeat("x", 32))))
+	AssertStatusCode(t, recorder, http.StatusOK)
+}
+
+func TestGroupSecurityHeaders(t *testing.T) {
+	tree := SetupTree()
+	pages := tree.Group("/", Middleware.NewSecurityHeaders())
+	api := tree.Group("/api", Middleware.NewAPISecurityHeaders())
+	var nonce string
+	AssertNoError(t, pages.SetHandler(GET, "/home", func(w http.ResponseWriter, r *http.Request, params *Param.Params) {
+		nonce = Middleware.GetCSPNonce(r.Context())
+	}), "pages SetHandler")
+	AssertNoError(t, api.SetHandler(GET, "/users", func(w http.ResponseWriter, r *http.Request, params *Param.Params) {}), "api SetHandler")
+
+	recorder := httptest.NewRecorder()
+	tree.ServeHTTP(recorder, httptest.NewRequest("GET", "/home", nil))
+	if nonce == "" || !strings.Contains(recorder.Header().Get("Content-Security-Policy"), "'nonce-"+nonce+"'") {
+		t.Errorf("Expected page CSP with nonce %q, got %q", nonce, recorder.Header().Get("Content-Security-Policy"))
+	}
+
+	recorder = httptest.NewRecorder()
+	tree.ServeHTTP(recorder, httptest.NewRequest("GET", "/api/users", nil))
+	if recorder.Header().Get("Content-Security-Policy") != "default-src 'none'; frame-ancestors 'none'" {
+		t.Errorf("Expected API CSP, got %q", recorder.Header().Get("Content-Security-Policy"))
+	}
+}
+
+func TestGroupIPFilter(t *testing.T) {
+	tree := SetupTree()
+	office, err := Middleware.NewIPFilter([]string{"198.51.100.0/24"}, nil)
+	AssertNoError(t, err, "NewIPFilter")
+	admin := tree.Group("/admin", office)
+	handler := func(w http.ResponseWriter, r *http.Request, params *Param.Params) {}
+	AssertNoError(t, admin.SetHandler(GET, "/users", handler), "admin SetHandler")
+	AssertNoError(t, tree.SetHandler(GET, "/users", handler), "tree SetHandler")
+
+	serve := func(path string, remote string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest("GET", path, nil)
+		request.RemoteAddr = remote
+		recorder := httptest.NewRecorder()
+		tree.ServeHTTP(recorder, request)
+		return recorder
+	}
+	AssertStatusCode(t, serve("/admin/users", "198.51.100.4:1000"), http.StatusOK)
+	AssertStatusCode(t, serve("/admin/users", "203.0.113.4:1000"), http.StatusForbidden)
+	AssertStatusCode(t, serve("/users", "203.0.113.4:1000"), http.StatusOK)
+	// Unknown paths under the prefix are filtered too instead of answering 404
+	AssertStatusCode(t, serve("/admin/unknown", "203.0.113.4:1000"), http.StatusForbidden)
+	AssertStatusCode(t, serve("/admin/unknown", "198.51.100.4:1000"), http.StatusNotFound)
+
+	// Reloaded lists apply to the already registered group
+	AssertNoError(t, office.Update([]string{"203.0.113.0/24"}, nil), "Update")
+	AssertStatusCode(t, serve("/admin/users", "203.0.113.4:1000"), http.StatusOK)
+	AssertStatusCode(t, serve("/admin/users", "198.51.100.4:1000"), http.StatusForbidden)
+}
+
+func TestGroupUnmatchedRequests(t *testing.T) {
+	tree := SetupTree()
+	api := tree.Group("/api", tagMiddleware("api"))
+	users := api.Group("/users/:id", tagMiddleware("users"))
+	handler := func(w http.ResponseWriter, r *http.Request, params *Param.Params) {}
+	AssertNoError(t, api.SetHandler(GET, "/status", handler), "api SetHandler")
+	AssertNoError(t, users.SetHandler(GET, "/posts", handler), "users SetHandler")
+	AssertNoError(t, tree.SetHandler(GET, "/apix", handler), "tree SetHandler")
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		status int
+		chain  []string
+	}{
+		{"not_found", "GET", "/api/missing", http.StatusNotFound, []string{"api"}},
+		{"not_allowed", "DELETE", "/api/status", http.StatusMethodNotAllowed, []string{"api"}},
+		{"unknown_method", "BREW", "/api/status", http.StatusMethodNotAllowed, []string{"api"}},
+		{"prefix_itself", "GET", "/api", http.StatusNotFound, []string{"api"}},
+		{"nested_group", "GET", "/api/users/7/missing", http.StatusNotFound, []string{"api", "users"}},
+		{"outside_group", "GET", "/missing", http.StatusNotFound, nil},
+		{"segment_prefix_only", "POST", "/apix", http.StatusMethodNotAllowed, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			tree.ServeHTTP(recorder, httptest.NewRequest(tt.method, tt.path, nil))
+			AssertStatusCode(t, recorder, tt.status)
+			if chain := recorder.Header().Values("X-Chain"); !slices.Equal(chain, tt.chain) {
+				t.Errorf("Expected chain %v, got %v", tt.chain, chain)
+			}
+		})
+	}
+}
+
+func TestGroupCORSPreflight(t *testing.T) {
+	tree := SetupTree()
+	api := tree.Group("/api", Middleware.NewCORS(&tree))
+	handler := func(w http.ResponseWriter, r *http.Request, params *Param.Params) {}
+	AssertNoError(t, api.SetHandler(GET, "/users/:id", handler), "GET SetHandler")
+	AssertNoError(t, api.SetHandler(PUT, "/users/:id", handler), "PUT SetHandler")
+
+	request := httptest.NewRequest("OPTIONS", "/api/users/7", nil)
+	request.Header.Set("Origin", "https://app.example.com")
+	request.Header.Set("Access-Control-Request-Method", "PUT")
+	recorder := httptest.NewRecorder()
+	tree.ServeHTTP(recorder, request)
+
+	AssertStatusCode(t, recorder, http.StatusNoContent)
+	if methods := recorder.Header().Get("Access-Control-Allow-Methods"); !strings.Contains(methods, "PUT") {
+		t.Errorf("Expected PUT in Access-Control-Allow-Methods, got %q", methods)
+	}
+}
@@ -0,0 +1,122 @@
+package Tree
+
+import (
+	"LiteFrame/Router/Middleware"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// ======================
+// Lookup Tests
+// ======================
+
+func TestLookup(t *testing.T) {
+	handler := CreateTestHandler()
+	tree, err := SetupTreeWithRoutes([]RouteConfig{
+		{"GET", "/", handler},
+		{"GET", "/users/:id/", handler},
+		{"POST", "/users/:id", handler},
+		{"GET", "/files/*path", handler},
+		{"GET", "/api/v1/health", handler},
+	})
+	AssertNoError(t, err, "SetupTreeWithRoutes")
+	tree.NotFoundHandler = CreateHandlerWithResponse("not found")
+	tree.NotAllowedHandler = CreateHandlerWithResponse("not allowed")
+
+	tests := []struct {
+		name    string
+		method  string
+		path    string
+		status  MatchStatus
+		pattern string
+		params  map[string]string
+	}{
+		{"root", "GET", "/", MatchFound, "/", nil},
+		{"wildcard", "GET", "/users/42", MatchFound, "/users/:id", map[string]string{"id": "42"}},
+		{"wildcard_other_method", "POST", "/users/42", MatchFound, "/users/:id", map[string]string{"id": "42"}},
+		{"catch_all", "GET", "/files/a/b.txt", MatchFound, "/files/*path", map[string]string{"path": "a/b.txt"}},
+		{"method_not_allowed", "DELETE", "/users/42", MatchMethodNotAllowed, "/users/:id", map[string]string{"id": "42"}},
+		{"unknown_method", "BREW", "/users/42", MatchMethodNotAllowed, "", nil},
+		{"not_found", "GET", "/missing", MatchNotFound, "", nil},
+		{"intermediate_node", "GET", "/api/v1", MatchNotFound, "", nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := tree.Lookup(test.method, test.path)
+			if result.Status != test.status {
+				t.Errorf("Expected status %s, got %s", test.status, result.Status)
+			}
+			if result.Pattern != test.pattern {
+				t.Errorf("Expected pattern %q, got %q", test.pattern, result.Pattern)
+			}
+			if result.Handler == nil {
+				t.Error("Expected non-nil handler")
+			}
+			if test.params == nil && result.Params != nil {
+				t.Errorf("Expected nil params, got %+v", result.Params)
+			}
+			for key, value := range test.params {
+				if result.Params == nil || result.Params.GetByName(key) != value {
+					t.Errorf("Expected param %s=%q, got %+v", key, value, result.Params)
+				}
+			}
+		})
+	}
+}
+
+func TestAllowedMethods(t *testing.T) {
+	handler := CreateTestHandler()
+	tree, err := SetupTreeWithRoutes([]RouteConfig{
+		{"GET", "/users/:id", handler},
+		{"PUT", "/users/:id", handler},
+		{"DELETE", "/users/:id", handler},
+		{"GET", "/api/v1/health", handler},
+	})
+	AssertNoError(t, err, "SetupTreeWithRoutes")
+
+	if methods := tree.AllowedMethods("/users/42"); len(methods) != 3 || methods[0] != "GET" || methods[1] != "PUT" || methods[2] != "DELETE" {
+		t.Errorf("Expected [GET PUT DELETE], got %v", methods)
+	}
+	if methods := tree.AllowedMethods("/api/v1"); methods != nil {
+		t.Errorf("Expected nil for intermediate node, got %v", methods)
+	}
+	if methods := tree.AllowedMethods("/missing"); methods != nil {
+		t.Errorf("Expected nil for unknown path, got %v", methods)
+	}
+
+	// Tree satisfies Middleware.MethodLister, so preflights use the registered methods
+	tree.SetMiddleware(Middleware.NewCORS(&tree))
+	tree.CompileMiddlewares()
+	request := httptest.NewRequest(http.MethodOptions, "/users/42", nil)
+	request.Header.Set("Origin", "https://site.test")
+	request.Header.Set("Access-Control-Request-Method", "PUT")
+	recorder := httptest.NewRecorder()
+	tree.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusNoContent {
+		t.Errorf("Expected 204, got %d", recorder.Code)
+	}
+	if allowed := recorder.Header().Get("Access-Control-Allow-Methods"); allowed != "GET, PUT, DELETE" {
+		t.Errorf("Expected registered methods, got %q", allowed)
+	}
+}
+
+func TestCleanPath(t *testing.T) {
+	tests := []TestCase{
+		{"Empty", "", "/"},
+		{"Root", "/", "/"},
+		{"Simple", "/users", "/users"},
+		{"TrailingSlash", "/users/", "/users"},
+		{"MultipleSlashes", "//users//:id//", "/users/:id"},
+		{"NoLeadingSlash", "files/*path", "/files/*path"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			if result := CleanPath(test.Input); result != test.Expected.(string) {
+				t.Errorf("CleanPath(%q) = %q, expected %q", test.Input, result, test.Expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package Tree
+
+import (
+	"LiteFrame/Router/Middleware"
+	"LiteFrame/Router/Param"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// ======================
+// Route Pattern Exposure Tests
+// ======================
+
+// patternRecorder is a middleware that records request.Pattern seen by the middleware chain
+type patternRecorder struct {
+	patterns *[]string
+}
+
+func (instance patternRecorder) GetHandler() Middleware.MiddleWareFunc {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request, params *Param.Params) {
+			next(w, r, params)
+			*instance.patterns = append(*instance.patterns, r.Pattern)
+		}
+	}
+}
+
+func TestRequestPattern(t *testing.T) {
+	tree := SetupTree()
+	tree.NotFoundHandler = func(w http.ResponseWriter, r *http.Request, params *Param.Params) {
+		w.WriteHeader(http.StatusNotFound)
+	}
+	tree.NotAllowedHandler = func(w http.ResponseWriter, r *http.Request, params *Param.Params) {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+	echo := func(w http.ResponseWriter, r *http.Request, params *Param.Params) {
+		_, _ = w.Write([]byte(r.Pattern))
+	}
+	for _, route := range []string{"/", "/users/:id", "/files/*path", "//api//v1/"} {
+		AssertNoError(t, tree.SetHandler(GET, route, echo), "SetHandler "+route)
+	}
+
+	t.Run("handler_sees_pattern", func(t *testing.T) {
+		tests := map[string]string{
+			"/":              "/",
+			"/users/42":      "/users/:id",
+			"/files/a/b.txt": "/files/*path",
+			"/api/v1":        "/api/v1",
+		}
+		for path, expected := range tests {
+			AssertResponseBody(t, ExecuteRequest(tree, "GET", path), expected)
+		}
+	})
+
+	t.Run("middleware_sees_pattern", func(t *testing.T) {
+		patterns := make([]string, 0)
+		tree.SetMiddleware(patternRecorder{patterns: &patterns})
+		tree.CompileMiddlewares()
+
+		for _, request := range []struct{ method, path string }{
+			{"GET", "/users/7"},
+			{"POST", "/users/7"},
+			{"GET", "/missing"},
+		} {
+			tree.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(request.method, request.path, nil))
+		}
+
+		expected := []string{"/users/:id", "/users/:id", ""}
+		if len(patterns) != len(expected) {
+			t.Fatalf("Expected %d patterns, got %v", len(expected), patterns)
+		}
+		for index := range expected {
+			if patterns[index] != expected[index] {
+				t.Errorf("Pattern[%d]: expected %q, got %q", index, expected[index], patterns[index])
+			}
+		}
+	})
+
+	t.Run("outer_pattern_cleared", func(t *testing.T) {
+		patterns := make([]string, 0)
+		tree.Middlewares = nil
+		tree.SetMiddleware(patternRecorder{patterns: &patterns})
+		tree.CompileMiddlewares()
+
+		// A pattern left by an outer http.ServeMux must not survive a miss
+		for _, request := range []struct{ method, path string }{
+			{"GET", "/missing"},
+			{"FOO", "/users/7"},
+			{"GET", "/users/7"},
+		} {
+			outer := httptest.NewRequest(request.method, request.path, nil)
+			outer.Pattern = "/"
+			tree.ServeHTTP(httptest.NewRecorder(), outer)
+		}
+
+		expected := []string{"", "", "/users/:id"}
+		if len(patterns) != len(expected) {
+			t.Fatalf("Expected %d patterns, got %v", len(expected), patterns)
+		}
+		for index := range expected {
+			if patterns[index] != expected[index] {
+				t.Errorf("Pattern[%d]: expected %q, got %q", index, expected[index], patterns[index])
+			}
+		}
+	})
+}
+
+// ======================
+// ServeHTTP Panic Tests
+// ======================
+
+func TestServeHTTP_PanicReturnsParams(t *testing.T) {
+	tree := SetupTree()
+	var captured *Param.Params
+	err := tree.SetHandler(GET, "/users/:id", func(w http.ResponseWriter, r *http.Request, params *Param.Params) {
+		captured = params
+		// Grow Overflow beyond Param.MaxSize so that Pool.Put visibly replaces it
+		for index := 0; index <= Param.MaxSize+2; index++ {
+			params.Add("extra", 0, 0)
+		}
+		panic("boom")
+	})
+	AssertNoError(t, err, "SetHandler")
+
+	func() {
+		defer func() { _ = recover() }()
+		tree.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/users/1", nil))
+	}()
+
+	if captured == nil {
+		t.Fatal("Expected handler to receive params")
+	}
+	if cap(captured.Overflow) > Param.MaxSize {
+		t.Error("Expected params to be returned to the pool after panic")
+	}
+}
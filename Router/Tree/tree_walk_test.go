@@ -0,0 +1,112 @@
+package Tree
+
+import (
+	"errors"
+	"testing"
+)
+
+// ======================
+// Walk / Routes Tests
+// ======================
+
+func TestRoutes(t *testing.T) {
+	t.Run("reconstructs_patterns", func(t *testing.T) {
+		handler := CreateTestHandler()
+		tree, err := SetupTreeWithRoutes([]RouteConfig{
+			{"GET", "/", handler},
+			{"GET", "/users", handler},
+			{"POST", "/users", handler},
+			{"GET", "/user/profile", handler},
+			{"GET", "/users/:id", handler},
+			{"DELETE", "/users/:id/posts/:postId", handler},
+			{"GET", "/files/*path", handler},
+			{"GET", "/api/v1/health", handler},
+		})
+		AssertNoError(t, err, "SetupTreeWithRoutes")
+
+		expected := []Route{
+			{Method: "GET", Pattern: "/"},
+			{Method: "GET", Pattern: "/api/v1/health"},
+			{Method: "GET", Pattern: "/files/*path"},
+			{Method: "GET", Pattern: "/user/profile"},
+			{Method: "GET", Pattern: "/users"},
+			{Method: "POST", Pattern: "/users"},
+			{Method: "GET", Pattern: "/users/:id"},
+			{Method: "DELETE", Pattern: "/users/:id/posts/:postId"},
+		}
+
+		routes := tree.Routes()
+		if len(routes) != len(expected) {
+			t.Fatalf("Expected %d routes, got %d: %v", len(expected), len(routes), routes)
+		}
+		for index, route := range routes {
+			if route.Method != expected[index].Method || route.Pattern != expected[index].Pattern {
+				t.Errorf("Route[%d]: expected %s %s, got %s %s", index,
+					expected[index].Method, expected[index].Pattern, route.Method, route.Pattern)
+			}
+			if route.Handler == nil {
+				t.Errorf("Route[%d]: expected handler, got nil", index)
+			}
+		}
+	})
+
+	t.Run("empty_tree", func(t *testing.T) {
+		tree := SetupTree()
+		if routes := tree.Routes(); len(routes) != 0 {
+			t.Errorf("Expected no routes, got %v", routes)
+		}
+	})
+}
+
+func TestWalk(t *testing.T) {
+	handler := CreateTestHandler()
+	tree, err := SetupTreeWithRoutes([]RouteConfig{
+		{"GET", "/a", handler},
+		{"GET", "/b", handler},
+		{"GET", "/c", handler},
+	})
+	AssertNoError(t, err, "SetupTreeWithRoutes")
+
+	t.Run("stops_on_error", func(t *testing.T) {
+		stop := errors.New("stop")
+		visited := 0
+		err := tree.Walk(func(method, pattern string, handler HandlerFunc) error {
+			visited++
+			if pattern == "/b" {
+				return stop
+			}
+			return nil
+		})
+		if !errors.Is(err, stop) {
+			t.Errorf("Expected stop error, got %v", err)
+		}
+		if visited != 2 {
+			t.Errorf("Expected 2 visited routes, got %d", visited)
+		}
+	})
+}
+
+func TestJoinPattern(t *testing.T) {
+	tree := SetupTree()
+
+	tests := []struct {
+		name     string
+		pattern  string
+		child    *Node
+		expected string
+	}{
+		{"root_static", "/", NewNode(StaticType, "users"), "/users"},
+		{"nested_static", "/users", NewNode(StaticType, "profile"), "/users/profile"},
+		{"partial_static", "/user", &Node{Type: StaticType, Path: "s", Partial: true}, "/users"},
+		{"wildcard", "/users", NewNode(WildCardType, ":id"), "/users/:id"},
+		{"catch_all", "/files", NewNode(CatchAllType, "*path"), "/files/*path"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if result := tree.JoinPattern(test.pattern, test.child); result != test.expected {
+				t.Errorf("JoinPattern(%q, %q) = %q, expected %q", test.pattern, test.child.Path, result, test.expected)
+			}
+		})
+	}
+}
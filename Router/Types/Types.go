@@ -14,4 +14,15 @@ import (
 // - http.ResponseWriter: Interface for writing HTTP responses
 // - *http.Request: Pointer to structure containing HTTP request information
 // - *Param.Params: Parameters extracted from URL path (nil if no parameters)
+//
+// The matched route pattern ("/users/:id") is available as request.Pattern.
 type HandlerFunc func(http.ResponseWriter, *http.Request, *Param.Params)
+
+// HandlerFuncWithError is an HTTP handler function type that reports failures by returning an error.
+// Registered through an adapter that forwards returned errors to the central ErrorHandlerFunc,
+// so handlers don't have to write error responses themselves.
+type HandlerFuncWithError func(http.ResponseWriter, *http.Request, *Param.Params) error
+
+// ErrorHandlerFunc is the central error handler type.
+// Turns an error returned by a HandlerFuncWithError into an HTTP response.
+type ErrorHandlerFunc func(http.ResponseWriter, *http.Request, error)